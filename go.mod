@@ -5,10 +5,8 @@ go 1.21
 require (
 	github.com/fatih/color v1.16.0
 	github.com/gomarkdown/markdown v0.0.0-20231222211730-1d6d20845b47
+	github.com/mattn/go-isatty v0.0.20
+	golang.org/x/sys v0.14.0
 )
 
-require (
-	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.14.0 // indirect
-)
+require github.com/mattn/go-colorable v0.1.13 // indirect
@@ -0,0 +1,34 @@
+// Package terminal queries properties of the terminal the CLI writes to.
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Width reports the number of columns of the terminal attached to f.
+// When f is not a terminal, it falls back to the COLUMNS environment
+// variable and then to fallback.
+func Width(f *os.File, fallback int) int {
+	if w, err := windowWidth(f.Fd()); err == nil && w > 0 {
+		return w
+	}
+	if w, ok := columnsFromEnv(); ok {
+		return w
+	}
+	return fallback
+}
+
+// columnsFromEnv parses $COLUMNS, ignoring unset or malformed values.
+func columnsFromEnv() (int, bool) {
+	value := strings.TrimSpace(os.Getenv("COLUMNS"))
+	if value == "" {
+		return 0, false
+	}
+	w, err := strconv.Atoi(value)
+	if err != nil || w <= 0 {
+		return 0, false
+	}
+	return w, true
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package terminal
+
+import "errors"
+
+// errUnsupported is returned on platforms without a window size ioctl.
+var errUnsupported = errors.New("window size query not supported on this platform")
+
+// windowWidth is not implemented on this platform; callers fall back to $COLUMNS.
+func windowWidth(fd uintptr) (int, error) {
+	return 0, errUnsupported
+}
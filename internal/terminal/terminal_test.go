@@ -0,0 +1,35 @@
+package terminal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWidth_NotATerminal_UsesColumns(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		name    string
+		columns string
+		want    int
+	}{
+		{name: "COLUMNS set", columns: "132", want: 132},
+		{name: "COLUMNS unset", columns: "", want: 100},
+		{name: "COLUMNS malformed", columns: "wide", want: 100},
+		{name: "COLUMNS negative", columns: "-5", want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COLUMNS", tt.columns)
+
+			if got := Width(f, 100); got != tt.want {
+				t.Errorf("Width() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
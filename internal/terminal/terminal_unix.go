@@ -0,0 +1,18 @@
+//go:build unix
+
+package terminal
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// windowWidth returns the column count reported by the TIOCGWINSZ ioctl.
+func windowWidth(fd uintptr) (int, error) {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query window size: %w", err)
+	}
+	return int(ws.Col), nil
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
+	"github.com/giovannirossini/markdown-render/internal/terminal"
 	"github.com/giovannirossini/markdown-render/render"
 )
 
 const exitCodeSuccess = 0
 const exitCodeError = 1
 
+// styleEnvVar names the environment variable holding a default style file path.
+const styleEnvVar = "MDRENDER_STYLE"
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "mdrender: %v\n", err)
@@ -20,26 +26,159 @@ func main() {
 }
 
 func run() error {
-	if len(os.Args) < 2 {
+	width := flag.Int("width", 0, "render width in columns (default: terminal width)")
+	themeName := flag.String("theme", "dark", "color theme: "+strings.Join(render.ThemeNames(), ", "))
+	colorFlag := flag.String("color", "auto", "when to use color: auto, always or never")
+	profileFlag := flag.String("color-profile", "auto", "color depth: auto, truecolor, 256 or 16")
+	noColor := flag.Bool("no-color", false, "disable color output (same as --color=never)")
+	lineNumbers := flag.Bool("line-numbers", false, "number the lines of code blocks")
+	listIndent := flag.Int("list-indent", 0, "columns to indent nested lists by (default: under the parent item's text)")
+	listGuides := flag.Bool("list-guides", false, "draw tree lines connecting nested list items")
+	taskSummary := flag.Bool("task-summary", false, "print a \"3/7 done\" count after lists with task items")
+	linksFlag := flag.String("links", "inline", "how to show link URLs: inline, osc8 (clickable links), footnote or hide")
+	linkRefsFlag := flag.String("link-refs", "end", "where --links=footnote lists URLs: end (of the document), section or paragraph")
+	footnotesFlag := flag.String("footnotes", "end", "where to print footnotes: end (of the document) or section (end of each top-level section)")
+	tableLayoutFlag := flag.String("table-layout", "auto", "table layout: auto, grid or records")
+	tableBorderFlag := flag.String("table-border", "light", "table border style: "+strings.Join(render.BorderStyleNames(), ", "))
+	tableRowLines := flag.Bool("table-row-lines", false, "draw a line between table rows")
+	truncateTables := flag.Bool("truncate-tables", false, "cut table cells that do not fit with \"...\" instead of wrapping them")
+	stylePath := flag.String("style", "", "path to a JSON style file (default: $"+styleEnvVar+")")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *listIndent < 0 {
+		return fmt.Errorf("invalid --list-indent %d: must not be negative", *listIndent)
+	}
+	if *width < 0 {
+		return fmt.Errorf("invalid --width %d: must be a positive number of columns", *width)
+	}
+
+	colorMode, err := render.ParseColorMode(*colorFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --color: %w", err)
+	}
+	if *noColor {
+		colorMode = render.ColorNever
+	}
+
+	colorProfile, err := render.ParseColorProfile(*profileFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --color-profile: %w", err)
+	}
+
+	links, err := render.ParseLinkMode(*linksFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --links: %w", err)
+	}
+	linkRefs, err := render.ParseLinkPlacement(*linkRefsFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --link-refs: %w", err)
+	}
+	footnotes, err := render.ParseFootnotePlacement(*footnotesFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --footnotes: %w", err)
+	}
+
+	tableLayout, err := render.ParseTableLayout(*tableLayoutFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --table-layout: %w", err)
+	}
+
+	tableBorder, err := render.ParseBorderStyle(*tableBorderFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --table-border: %w", err)
+	}
+
+	theme, err := loadTheme(*themeName, *stylePath)
+	if err != nil {
+		return err
+	}
+
+	opts := render.DefaultOptions()
+	opts.Theme = theme
+	opts.ColorMode = colorMode
+	opts.ColorProfile = colorProfile
+	opts.CodeLineNumbers = *lineNumbers
+	opts.ListIndent = *listIndent
+	opts.ListGuides = *listGuides
+	opts.TaskSummary = *taskSummary
+	opts.FootnotePlacement = footnotes
+	opts.LinkMode = links
+	opts.LinkPlacement = linkRefs
+	opts.TableLayout = tableLayout
+	opts.TableBorder = tableBorder
+	opts.TableRowSeparators = *tableRowLines
+	opts.TruncateTableCells = *truncateTables
+	opts.Width = *width
+	if opts.Width == 0 {
+		opts.Width = terminal.Width(os.Stdout, render.DefaultWidth)
+	}
+
+	content, err := readInput(flag.Args())
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(render.RenderToStringWithOptions(content, opts))
+	return nil
+}
+
+// loadTheme resolves the theme to render with. An explicit --style wins,
+// then $MDRENDER_STYLE unless --theme was given on the command line,
+// then the built-in theme named by --theme.
+func loadTheme(themeName, stylePath string) (*render.Theme, error) {
+	if stylePath == "" && !isFlagSet("theme") {
+		stylePath = os.Getenv(styleEnvVar)
+	}
+
+	if stylePath != "" {
+		return render.LoadStyleFile(stylePath)
+	}
+
+	theme, err := render.ThemeByName(themeName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --theme: %w", err)
+	}
+	return theme, nil
+}
+
+// isFlagSet reports whether the named flag was passed on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// readInput returns the markdown to render: stdin when no argument is given,
+// otherwise the named file, or the argument itself when no such file exists.
+func readInput(args []string) (string, error) {
+	if len(args) < 1 {
 		// Read from stdin
 		content, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return fmt.Errorf("error reading stdin: %w", err)
+			return "", fmt.Errorf("error reading stdin: %w", err)
 		}
-		render.Render(string(content))
-		return nil
+		return string(content), nil
 	}
 
-	input := os.Args[1]
+	input := args[0]
 
 	// Try to read as file
 	content, err := os.ReadFile(input)
 	if err != nil {
 		// Treat as direct markdown input if file doesn't exist
-		render.Render(input)
-		return nil
+		return input, nil
 	}
 
-	render.Render(string(content))
-	return nil
+	return string(content), nil
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: mdrender [flags] [file | markdown]\n\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "Renders markdown from a file, an argument or stdin.\n\nFlags:\n")
+	flag.PrintDefaults()
 }
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// alertKind is the type of a GitHub alert ("> [!NOTE]") or an aside.
+type alertKind int
+
+const (
+	alertNote alertKind = iota
+	alertTip
+	alertImportant
+	alertWarning
+	alertCaution
+	alertAside
+)
+
+// alertKinds holds the title and icon of each alert kind, indexed by alertKind.
+// The marker is the word between "[!" and "]" that starts a GitHub alert.
+var alertKinds = []struct {
+	marker, title, icon string
+}{
+	alertNote:      {"NOTE", "Note", "ℹ"},
+	alertTip:       {"TIP", "Tip", "★"},
+	alertImportant: {"IMPORTANT", "Important", "‼"},
+	alertWarning:   {"WARNING", "Warning", "⚠"},
+	alertCaution:   {"CAUTION", "Caution", "✖"},
+	alertAside:     {"", "Aside", "»"},
+}
+
+// alertMarker returns the alert kind of a blockquote that starts with a GitHub
+// alert marker such as "[!NOTE]" on a line of its own, and the text holding it.
+func alertMarker(quote *ast.BlockQuote) (alertKind, *ast.Text, bool) {
+	para, ok := ast.GetFirstChild(quote).(*ast.Paragraph)
+	if !ok {
+		return 0, nil, false
+	}
+	text, ok := ast.GetFirstChild(para).(*ast.Text)
+	if !ok {
+		return 0, nil, false
+	}
+	line, _, _ := strings.Cut(string(text.Literal), "\n")
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[!") || !strings.HasSuffix(line, "]") {
+		return 0, nil, false
+	}
+	marker := strings.ToUpper(line[2 : len(line)-1])
+	for kind, k := range alertKinds {
+		if k.marker != "" && k.marker == marker {
+			return alertKind(kind), text, true
+		}
+	}
+	return 0, nil, false
+}
+
+// stripAlertMarker removes the marker line from text, dropping its paragraph
+// when nothing else is left in it.
+func stripAlertMarker(text *ast.Text) {
+	_, rest, _ := strings.Cut(string(text.Literal), "\n")
+	text.Literal = []byte(strings.TrimLeft(rest, " "))
+	para := text.GetParent()
+	if len(text.Literal) == 0 {
+		ast.RemoveFromTree(text)
+		// A line break may separate the marker from text that follows it
+		if next := ast.GetFirstChild(para); next != nil {
+			switch next.(type) {
+			case *ast.Softbreak, *ast.Hardbreak:
+				ast.RemoveFromTree(next)
+			}
+		}
+	}
+	if len(para.GetChildren()) == 0 {
+		ast.RemoveFromTree(para)
+	}
+}
+
+// renderAlert draws the blocks of an alert or aside in a box spanning maxWidth
+// columns, with the icon and title of kind in the top border.
+func (r *ANSIRenderer) renderAlert(node ast.Node, kind alertKind, maxWidth int) string {
+	var buf strings.Builder
+	style := r.opts.Theme.alertStyle(kind)
+	k := alertKinds[kind]
+
+	boxWidth, textWidth := boxWidths(maxWidth)
+	// Too narrow for a box around the content, the title heads it instead
+	if textWidth < minNestedWidth {
+		buf.WriteString("\n")
+		buf.WriteString(r.paint(style.merge(Style{Bold: true}), truncateWidth(k.icon+" "+k.title, maxWidth, "")) + "\n")
+		for _, line := range r.renderBlocks(node.GetChildren(), maxWidth) {
+			buf.WriteString(line + "\n")
+		}
+		return buf.String()
+	}
+
+	label := truncateWidth(k.icon+" "+k.title, max(boxWidth-4, 1), "")
+	rest := max(boxWidth-displayWidth(label)-3, 0)
+
+	buf.WriteString("\n")
+	buf.WriteString(r.paint(style, "┌─ "))
+	buf.WriteString(r.paint(style.merge(Style{Bold: true}), label))
+	buf.WriteString(r.paint(style, " "+strings.Repeat("─", rest)+"┐\n"))
+	for _, line := range r.renderBlocks(node.GetChildren(), textWidth) {
+		line = truncateWidth(line, textWidth, "")
+		buf.WriteString(r.paint(style, "│ "))
+		buf.WriteString(padRight(line, textWidth))
+		buf.WriteString(r.paint(style, " │\n"))
+	}
+	buf.WriteString(r.paint(style, "└"+strings.Repeat("─", boxWidth)+"┘\n"))
+	return buf.String()
+}
+
+// calloutMarker returns the circled number for an mmark code callout such as <<3>>,
+// or the number in parentheses past the circled numbers Unicode has.
+func calloutMarker(id []byte) string {
+	n, err := strconv.Atoi(string(id))
+	if err != nil || n < 1 || n > 20 {
+		return "(" + string(id) + ")"
+	}
+	return string(rune('①' + n - 1))
+}
+
+// keepCalloutText stops p from taking the "<N>" inside an mmark <<N>> callout for an
+// HTML tag, so callouts outside code, where they mean nothing, read as written.
+func keepCalloutText(p *parser.Parser) {
+	var leftAngle func(p *parser.Parser, data []byte, offset int) (int, ast.Node)
+	leftAngle = p.RegisterInline('<', func(p *parser.Parser, data []byte, offset int) (int, ast.Node) {
+		// The second '<' of a callout is tried as well once the first is passed over
+		start := offset
+		if start > 0 && data[start-1] == '<' {
+			start--
+		}
+		if _, consumed := parser.IsCallout(data[start:]); consumed > 0 {
+			return 0, nil
+		}
+		return leftAngle(p, data, offset)
+	})
+}
+
+// parseAside is a block parser hook for mmark asides, lines starting with "A>".
+// The parser only recognises them with the rest of the mmark syntax turned on, which
+// would change how ordinary documents read. The aside runs to the last line with the
+// prefix, and what follows the prefixes is parsed as its content.
+func parseAside(data []byte) (ast.Node, []byte, int) {
+	var content bytes.Buffer
+	size := 0
+	for size < len(data) {
+		line, _, found := bytes.Cut(data[size:], []byte("\n"))
+		body, ok := asideLine(line)
+		if !ok {
+			break
+		}
+		content.Write(body)
+		content.WriteByte('\n')
+		size += len(line)
+		if found {
+			size++
+		}
+	}
+	if size == 0 {
+		return nil, nil, 0
+	}
+	return &ast.Aside{}, content.Bytes(), size
+}
+
+// asideLine returns the content of a line that starts with the "A>" aside prefix,
+// which may be indented by up to three spaces and followed by a space.
+func asideLine(line []byte) ([]byte, bool) {
+	indent := countLeading(string(line), ' ')
+	if indent > 3 || !bytes.HasPrefix(line[indent:], []byte("A>")) {
+		return nil, false
+	}
+	body := line[indent+2:]
+	return bytes.TrimPrefix(body, []byte(" ")), true
+}
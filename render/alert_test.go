@@ -0,0 +1,126 @@
+package render
+
+import (
+	"testing"
+)
+
+func TestRender_Alerts(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Note",
+			markdown: "> [!NOTE]\n> Useful information.",
+			contains: []string{"┌─ ℹ Note ─", "│ Useful information.", "└─"},
+			excludes: []string{"[!NOTE]"},
+		},
+		{
+			name:     "Each kind has its title and icon",
+			markdown: "> [!TIP]\n> a\n\nx\n\n> [!IMPORTANT]\n> b\n\nx\n\n> [!WARNING]\n> c\n\nx\n\n> [!CAUTION]\n> d",
+			contains: []string{"★ Tip", "‼ Important", "⚠ Warning", "✖ Caution"},
+		},
+		{
+			name:     "Markers are case-insensitive",
+			markdown: "> [!note]\n> lower case",
+			contains: []string{"ℹ Note", "│ lower case"},
+		},
+		{
+			name:     "Several paragraphs",
+			markdown: "> [!TIP]\n> First.\n>\n> Second.",
+			contains: []string{"│ First.", "│                                      │\n│ Second."},
+		},
+		{
+			name:     "Unknown markers stay a quote",
+			markdown: "> [!TODO]\n> not an alert",
+			contains: []string{"│ [!TODO]"},
+			excludes: []string{"┌"},
+		},
+		{
+			name:     "Marker followed by text stays a quote",
+			markdown: "> [!NOTE] inline",
+			contains: []string{"│ [!NOTE] inline"},
+			excludes: []string{"┌"},
+		},
+		{
+			name:     "Footnotes inside an alert",
+			markdown: "> [!NOTE]\n> See this[^a].\n\n[^a]: The note.",
+			contains: []string{"│ See this¹.", "Notes\n1. The note."},
+		},
+		{
+			name:     "Long text wraps inside the box",
+			markdown: "> [!WARNING]\n> This warning is long enough that it has to wrap inside the box.",
+			contains: []string{"│ This warning is long enough that it  │", "│ has to wrap inside the box."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Width: 40, ColorMode: ColorNever}
+			result := RenderToStringWithOptions(tt.markdown, opts)
+			for _, want := range tt.contains {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > opts.Width {
+				t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+			}
+		})
+	}
+}
+
+func TestRender_AlertStyle(t *testing.T) {
+	theme := DarkTheme()
+	theme.Alerts.Caution = Style{Foreground: "red"}
+	opts := Options{Width: 40, Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+
+	result := RenderToStringWithOptions("> [!CAUTION]\n> Hot.", opts)
+	// 31 is the SGR parameter for a red foreground
+	if !contains(result, "\x1b[31m┌─ ") {
+		t.Errorf("Expected the caution border to be red, got %q", result)
+	}
+}
+
+func TestRender_Aside(t *testing.T) {
+	result := RenderToStringWithOptions("A> An aside about *this*\nA> and more.\n\nAfter.", Options{Width: 40, ColorMode: ColorNever})
+
+	for _, want := range []string{"┌─ » Aside ─", "│ An aside about this and more.", "After."} {
+		if !contains(result, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+		}
+	}
+	if contains(result, "A>") {
+		t.Errorf("Expected the aside prefix to be removed, got:\n%s", result)
+	}
+}
+
+func TestRender_Callouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{name: "Circled number in code", markdown: "```go\nx := 1 // <<1>>\n```", want: "x := 1 // ①"},
+		{name: "Beyond circled numbers", markdown: "```\nsee <<21>>\n```", want: "see (21)"},
+		{name: "Prose keeps callouts as written", markdown: "Shift x <<2>> bits.", want: "Shift x <<2>> bits."},
+		{name: "Other angle brackets are kept", markdown: "Mail <me@example.com> now", want: "me@example.com"},
+		{name: "Inline code keeps callouts as written", markdown: "Use `a <<1>> b`.", want: "a <<1>> b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{ColorMode: ColorNever})
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.want, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// quoteBar is the gutter drawn in front of every line of a blockquote.
+const quoteBar = "│ "
+
+// renderQuote renders the blocks of a blockquote within maxWidth columns, with the
+// quote bar in front of every line. The content is laid out in the space right of
+// the bar, so wrapped lines, lists, code blocks and tables all stay inside the
+// gutter, and a nested quote adds a bar of its own. Quotes nested too deep to leave
+// the content minNestedWidth columns beside another bar go without.
+func (r *ANSIRenderer) renderQuote(quote *ast.BlockQuote, maxWidth int) string {
+	var buf strings.Builder
+	gutter := quoteBar
+	if maxWidth-displayWidth(gutter) < minNestedWidth {
+		gutter = ""
+	}
+	bar := r.paint(r.opts.Theme.BlockQuote, gutter)
+	for _, line := range r.renderBlocks(quote.GetChildren(), maxWidth-displayWidth(gutter)) {
+		if line == "" {
+			// No trailing space on blank lines between the quoted blocks
+			buf.WriteString(r.paint(r.opts.Theme.BlockQuote, strings.TrimRight(gutter, " ")) + "\n")
+			continue
+		}
+		buf.WriteString(bar + line + "\n")
+	}
+	return buf.String()
+}
@@ -1,6 +1,7 @@
 package render
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -34,3 +35,100 @@ func TestRender_Blockquotes(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_BlockquoteGutter(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string // lines that must appear, bars included
+	}{
+		{
+			name:     "Wrapped lines keep the bar",
+			markdown: "> A long quoted paragraph that needs to wrap onto several lines here.",
+			want:     []string{"│ A long quoted paragraph that needs to", "│ wrap onto several lines here."},
+		},
+		{
+			name:     "Hard breaks keep the bar",
+			markdown: "> first\\\n> second",
+			want:     []string{"│ first", "│ second"},
+		},
+		{
+			name:     "Nested quotes add a bar",
+			markdown: "> outer\n>\n> > inner",
+			want:     []string{"│ outer", "│", "│ │ inner"},
+		},
+		{
+			name:     "Lists stay inside the gutter",
+			markdown: "> - one\n> - two",
+			want:     []string{"│ • one", "│ • two"},
+		},
+		{
+			name:     "Code blocks stay inside the gutter",
+			markdown: "> ```\n> x := 1\n> ```",
+			want:     []string{"│ ┌────", "│ │ x := 1", "│ └────"},
+		},
+		{
+			name:     "Tables stay inside the gutter",
+			markdown: "> | a | b |\n> |---|---|\n> | 1 | 2 |",
+			want:     []string{"│ ┌─────┬─────┐", "│ │ 1   │ 2   │"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Width: 40, ColorMode: ColorNever}
+			result := RenderToStringWithOptions(tt.markdown, opts)
+			lines := strings.Split(result, "\n")
+			for _, want := range tt.want {
+				found := false
+				for _, line := range lines {
+					if strings.HasPrefix(line, want) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected a line starting with %q, got:\n%s", want, result)
+				}
+			}
+			for _, line := range lines {
+				if line != "" && !strings.HasPrefix(line, "│") {
+					t.Errorf("Expected every line to carry the quote bar, got %q in:\n%s", line, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > opts.Width {
+				t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+			}
+		})
+	}
+}
+
+func TestRender_DeepBlockquoteWidth(t *testing.T) {
+	body := "text that is long enough to wrap a few times\n\n" +
+		"```go\nfunc main() { fmt.Println(1) }\n```\n\n" +
+		"| a | b |\n|---|---|\n| 1 | 2 |\n\n" +
+		"> [!NOTE]\n> note body text\n\n" +
+		"- item one\n  - nested item\n\n" +
+		"---"
+
+	for _, depth := range []int{1, 3, 6, 10} {
+		t.Run(strings.Repeat(">", depth), func(t *testing.T) {
+			prefix := strings.Repeat("> ", depth)
+			lines := strings.Split(body, "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimRight(prefix+line, " ")
+			}
+			opts := Options{Width: 20, ColorMode: ColorNever}
+			result := RenderToStringWithOptions(strings.Join(lines, "\n"), opts)
+			if got := maxVisibleLineWidth(result); got > opts.Width {
+				t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+			}
+			// However deep, the content keeps enough columns for whole words
+			for _, want := range []string{"text that", "note body", "item one"} {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
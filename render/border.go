@@ -0,0 +1,164 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BorderStyle selects the characters tables are drawn with.
+type BorderStyle int
+
+const (
+	// BorderLight draws thin box-drawing lines: ┌─┬─┐.
+	BorderLight BorderStyle = iota
+	// BorderRounded is BorderLight with rounded corners: ╭─┬─╮.
+	BorderRounded
+	// BorderHeavy draws thick lines: ┏━┳━┓.
+	BorderHeavy
+	// BorderDouble draws double lines: ╔═╦═╗.
+	BorderDouble
+	// BorderASCII uses only +, - and | for legacy terminals and log files.
+	BorderASCII
+	// BorderMarkdown draws pipe tables that are valid markdown again.
+	BorderMarkdown
+	// BorderNone separates columns with spaces and underlines the header only.
+	BorderNone
+)
+
+// borderStyleNames maps flag spellings to border styles, in the order they are listed.
+var borderStyleNames = []struct {
+	name  string
+	style BorderStyle
+}{
+	{"light", BorderLight},
+	{"rounded", BorderRounded},
+	{"heavy", BorderHeavy},
+	{"double", BorderDouble},
+	{"ascii", BorderASCII},
+	{"markdown", BorderMarkdown},
+	{"none", BorderNone},
+}
+
+// String returns the flag spelling of b.
+func (b BorderStyle) String() string {
+	for _, entry := range borderStyleNames {
+		if entry.style == b {
+			return entry.name
+		}
+	}
+	return "light"
+}
+
+// BorderStyleNames returns the accepted border style names.
+func BorderStyleNames() []string {
+	names := make([]string, len(borderStyleNames))
+	for i, entry := range borderStyleNames {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// ParseBorderStyle parses a border style name such as "rounded" or "ascii".
+func ParseBorderStyle(s string) (BorderStyle, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "" {
+		return BorderLight, nil
+	}
+	for _, entry := range borderStyleNames {
+		if entry.name == name {
+			return entry.style, nil
+		}
+	}
+	return BorderLight, fmt.Errorf("unknown border style %q (expected one of: %s)", s, strings.Join(BorderStyleNames(), ", "))
+}
+
+// borderLine is one horizontal line of a table. A line without fill is not drawn.
+type borderLine struct {
+	left, fill, junction, right string
+	// alignMarks replaces the fill next to a junction with ':' to mark column alignment.
+	alignMarks bool
+}
+
+// borderSet holds every glyph needed to draw a table.
+type borderSet struct {
+	top, header, row, footer, bottom borderLine
+	// Vertical separators at the start of a line, between cells and at the end
+	left, middle, right string
+}
+
+// borderSets maps each style to its glyphs.
+var borderSets = map[BorderStyle]borderSet{
+	BorderLight: {
+		top:    borderLine{left: "┌", fill: "─", junction: "┬", right: "┐"},
+		header: borderLine{left: "├", fill: "─", junction: "┼", right: "┤"},
+		row:    borderLine{left: "├", fill: "─", junction: "┼", right: "┤"},
+		footer: borderLine{left: "╞", fill: "═", junction: "╪", right: "╡"},
+		bottom: borderLine{left: "└", fill: "─", junction: "┴", right: "┘"},
+		left:   "│", middle: "│", right: "│",
+	},
+	BorderRounded: {
+		top:    borderLine{left: "╭", fill: "─", junction: "┬", right: "╮"},
+		header: borderLine{left: "├", fill: "─", junction: "┼", right: "┤"},
+		row:    borderLine{left: "├", fill: "─", junction: "┼", right: "┤"},
+		footer: borderLine{left: "╞", fill: "═", junction: "╪", right: "╡"},
+		bottom: borderLine{left: "╰", fill: "─", junction: "┴", right: "╯"},
+		left:   "│", middle: "│", right: "│",
+	},
+	BorderHeavy: {
+		top:    borderLine{left: "┏", fill: "━", junction: "┳", right: "┓"},
+		header: borderLine{left: "┣", fill: "━", junction: "╋", right: "┫"},
+		row:    borderLine{left: "┣", fill: "━", junction: "╋", right: "┫"},
+		footer: borderLine{left: "┣", fill: "━", junction: "╋", right: "┫"},
+		bottom: borderLine{left: "┗", fill: "━", junction: "┻", right: "┛"},
+		left:   "┃", middle: "┃", right: "┃",
+	},
+	BorderDouble: {
+		top:    borderLine{left: "╔", fill: "═", junction: "╦", right: "╗"},
+		header: borderLine{left: "╠", fill: "═", junction: "╬", right: "╣"},
+		row:    borderLine{left: "╠", fill: "═", junction: "╬", right: "╣"},
+		footer: borderLine{left: "╠", fill: "═", junction: "╬", right: "╣"},
+		bottom: borderLine{left: "╚", fill: "═", junction: "╩", right: "╝"},
+		left:   "║", middle: "║", right: "║",
+	},
+	BorderASCII: {
+		top:    borderLine{left: "+", fill: "-", junction: "+", right: "+"},
+		header: borderLine{left: "+", fill: "=", junction: "+", right: "+"},
+		row:    borderLine{left: "+", fill: "-", junction: "+", right: "+"},
+		footer: borderLine{left: "+", fill: "=", junction: "+", right: "+"},
+		bottom: borderLine{left: "+", fill: "-", junction: "+", right: "+"},
+		left:   "|", middle: "|", right: "|",
+	},
+	BorderMarkdown: {
+		header: borderLine{left: "|", fill: "-", junction: "|", right: "|", alignMarks: true},
+		footer: borderLine{left: "|", fill: "=", junction: "|", right: "|"},
+		left:   "|", middle: "|", right: "|",
+	},
+	BorderNone: {
+		header: borderLine{fill: "─", junction: " "},
+		footer: borderLine{fill: "─", junction: " "},
+		middle: " ",
+	},
+}
+
+// tableBorders returns the glyphs for the configured border style.
+func (r *ANSIRenderer) tableBorders() borderSet {
+	if set, ok := borderSets[r.opts.TableBorder]; ok {
+		return set
+	}
+	return borderSets[BorderLight]
+}
+
+// overhead returns the columns a row of n cells spends on borders and cell padding.
+func (b borderSet) overhead(n int) int {
+	return displayWidth(b.left) + displayWidth(b.right) + (n-1)*displayWidth(b.middle) + 2*n
+}
+
+// ruleFill returns the glyph used for rules between stacked records.
+func (b borderSet) ruleFill() string {
+	for _, fill := range []string{b.row.fill, b.header.fill} {
+		if fill != "" {
+			return fill
+		}
+	}
+	return "─"
+}
@@ -0,0 +1,123 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBorderStyle(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BorderStyle
+		wantErr bool
+	}{
+		{input: "", want: BorderLight},
+		{input: "light", want: BorderLight},
+		{input: "Rounded", want: BorderRounded},
+		{input: "heavy", want: BorderHeavy},
+		{input: "double", want: BorderDouble},
+		{input: "ascii", want: BorderASCII},
+		{input: "markdown", want: BorderMarkdown},
+		{input: "none", want: BorderNone},
+		{input: "dotted", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBorderStyle(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBorderStyle(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseBorderStyle(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_TableBorders(t *testing.T) {
+	markdown := "| Name | Value |\n|:-----|------:|\n| alpha | 1 |\n| beta | 22 |"
+
+	tests := []struct {
+		name          string
+		border        BorderStyle
+		rowSeparators bool
+		want          []string
+		excludes      []string
+	}{
+		{
+			name:   "Light",
+			border: BorderLight,
+			want:   []string{"┌───────┬───────┐", "├───────┼───────┤", "│ beta  │    22 │", "└───────┴───────┘"},
+		},
+		{
+			name:   "Rounded",
+			border: BorderRounded,
+			want:   []string{"╭───────┬───────╮", "╰───────┴───────╯"},
+		},
+		{
+			name:   "Heavy",
+			border: BorderHeavy,
+			want:   []string{"┏━━━━━━━┳━━━━━━━┓", "┃ alpha ┃     1 ┃", "┗━━━━━━━┻━━━━━━━┛"},
+		},
+		{
+			name:   "Double",
+			border: BorderDouble,
+			want:   []string{"╔═══════╦═══════╗", "╠═══════╬═══════╣", "╚═══════╩═══════╝"},
+		},
+		{
+			name:          "ASCII with row separators",
+			border:        BorderASCII,
+			rowSeparators: true,
+			want:          []string{"+-------+-------+\n| Name  | Value |\n+=======+=======+\n| alpha |     1 |\n+-------+-------+\n| beta  |    22 |\n+-------+-------+"},
+		},
+		{
+			name:   "Markdown",
+			border: BorderMarkdown,
+			want:   []string{"| Name  | Value |\n|:------|------:|\n| alpha |     1 |\n| beta  |    22 |"},
+		},
+		{
+			name:     "None",
+			border:   BorderNone,
+			want:     []string{" Name    Value\n─────── ───────\n alpha       1\n beta       22\n"},
+			excludes: []string{"│", "┌", "└"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Width: 40, ColorMode: ColorNever, TableBorder: tt.border, TableRowSeparators: tt.rowSeparators}
+			result := RenderToStringWithOptions(markdown, opts)
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+			if tt.border == BorderASCII {
+				for _, r := range result {
+					if r > 0x7f {
+						t.Fatalf("Expected ASCII-only output, found %q in:\n%s", r, result)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRender_TableBordersFitWidth(t *testing.T) {
+	markdown := "| A | B | C |\n|---|---|---|\n| " + strings.Repeat("long text ", 10) + "| x | y |"
+
+	for _, border := range []BorderStyle{BorderLight, BorderASCII, BorderMarkdown, BorderNone} {
+		t.Run(border.String(), func(t *testing.T) {
+			result := RenderToStringWithOptions(markdown, Options{Width: 40, ColorMode: ColorNever, TableBorder: border})
+			if got := maxVisibleLineWidth(result); got > 40 {
+				t.Errorf("Expected lines at most 40 columns wide, got %d:\n%s", got, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// codeTabWidth is the distance between tab stops inside code blocks.
+const codeTabWidth = 4
+
+// expandTabs replaces each tab in s with the spaces up to the next tab stop, counting
+// columns from the start of its line, so text after tabs lines up as in an editor.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var buf strings.Builder
+	col := 0
+	for s != "" {
+		switch s[0] {
+		case '\t':
+			n := tabWidth - col%tabWidth
+			buf.WriteString(strings.Repeat(" ", n))
+			col += n
+			s = s[1:]
+			continue
+		case '\n':
+			col = 0
+			buf.WriteByte('\n')
+			s = s[1:]
+			continue
+		}
+		size, width := nextCluster(s)
+		buf.WriteString(s[:size])
+		col += width
+		s = s[size:]
+	}
+	return buf.String()
+}
+
+// boxWidths returns the sizes of a box spanning maxWidth columns: the border run
+// between its two corners, and the text inside it once a space of padding is left
+// on both sides.
+func boxWidths(maxWidth int) (border, text int) {
+	border = max(maxWidth-2, 0)
+	return border, max(border-2, 1)
+}
+
+// renderCodeBlock draws a fenced or indented code block inside a box spanning maxWidth columns.
+// The fence language is shown in the top border, and fence attributes can turn on
+// line numbers and highlight individual lines.
+func (r *ANSIRenderer) renderCodeBlock(n *ast.CodeBlock, maxWidth int) string {
+	var buf strings.Builder
+	theme := r.opts.Theme
+
+	fence := parseFenceInfo(string(n.Info))
+
+	// Tabs would be expanded by the terminal and push the right border out of line
+	code := expandTabs(string(n.Literal), codeTabWidth)
+	lines, highlighted := highlightCode(fence.language, code)
+	lines = markCallouts(lines)
+	// Skip the last line if it's empty (trailing newline)
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	lineNumbers := r.opts.CodeLineNumbers
+	if fence.lineNumbers != nil {
+		lineNumbers = *fence.lineNumbers
+	}
+	gutterDigits := 0
+	if lineNumbers {
+		gutterDigits = len(strconv.Itoa(fence.lineStart + len(lines) - 1))
+	}
+
+	boxWidth, textWidth := boxWidths(maxWidth)
+	if lineNumbers {
+		// "12 │ " in front of every line
+		textWidth = max(textWidth-(gutterDigits+3), 1)
+	}
+
+	buf.WriteString("\n")
+	buf.WriteString(r.codeBlockTopBorder(fence.language, boxWidth))
+
+	for i, line := range lines {
+		lineStyle := Style{}
+		if fence.highlight.contains(i + 1) {
+			lineStyle = theme.CodeHighlightLine
+		}
+
+		// Wrap long lines within code blocks
+		for j, chunk := range chunkTokens(line, textWidth) {
+			buf.WriteString(r.paint(theme.CodeBlockBorder, "│ "))
+			if lineNumbers {
+				number := ""
+				if j == 0 {
+					number = strconv.Itoa(fence.lineStart + i)
+				}
+				buf.WriteString(r.paint(theme.CodeLineNumber, fmt.Sprintf("%*s", gutterDigits, number)))
+				buf.WriteString(r.paint(theme.CodeBlockBorder, " │ "))
+			}
+			used := 0
+			for _, tok := range chunk {
+				buf.WriteString(r.paint(r.tokenStyle(tok.kind, highlighted).merge(lineStyle), tok.text))
+				used += displayWidth(tok.text)
+			}
+			// Pad the line to ensure the right border aligns; highlighted lines are filled to the border
+			if used < textWidth {
+				buf.WriteString(r.paint(lineStyle, strings.Repeat(" ", textWidth-used)))
+			}
+			buf.WriteString(r.paint(theme.CodeBlockBorder, " │\n"))
+		}
+	}
+
+	buf.WriteString(r.paint(theme.CodeBlockBorder, "└"+strings.Repeat("─", boxWidth)+"┘\n"))
+	return buf.String()
+}
+
+// codeBlockTopBorder draws the top edge of a code box, embedding the language label when known.
+func (r *ANSIRenderer) codeBlockTopBorder(language string, boxWidth int) string {
+	theme := r.opts.Theme
+	// "─ " before and " " after the label, with at least one dash to its right
+	maxLabel := boxWidth - 4
+	if language == "" || maxLabel < 1 {
+		return r.paint(theme.CodeBlockBorder, "┌"+strings.Repeat("─", boxWidth)+"┐\n")
+	}
+
+	label := truncateWidth(language, maxLabel, "")
+	rest := max(boxWidth-displayWidth(label)-3, 0)
+
+	return r.paint(theme.CodeBlockBorder, "┌─ ") +
+		r.paint(theme.CodeBlockLabel, label) +
+		r.paint(theme.CodeBlockBorder, " "+strings.Repeat("─", rest)+"┐\n")
+}
+
+// tokenStyle returns the style for a token, falling back to the plain code block style.
+// Plain text in a highlighted language uses the syntax text style instead.
+func (r *ANSIRenderer) tokenStyle(kind tokenKind, highlighted bool) Style {
+	syntax := r.opts.Theme.Syntax
+	var style Style
+	switch kind {
+	case tokenText:
+		if highlighted {
+			style = syntax.Text
+		}
+	case tokenKeyword:
+		style = syntax.Keyword
+	case tokenType:
+		style = syntax.Type
+	case tokenLiteral:
+		style = syntax.Literal
+	case tokenString:
+		style = syntax.String
+	case tokenNumber:
+		style = syntax.Number
+	case tokenComment:
+		style = syntax.Comment
+	case tokenOperator:
+		style = syntax.Operator
+	case tokenFunction:
+		style = syntax.Function
+	case tokenVariable:
+		style = syntax.Variable
+	case tokenKey:
+		style = syntax.Key
+	case tokenInserted:
+		style = syntax.Inserted
+	case tokenDeleted:
+		style = syntax.Deleted
+	case tokenMeta:
+		style = syntax.Meta
+	case tokenCallout:
+		style = r.opts.Theme.Callout
+	}
+	if style.isZero() {
+		return r.opts.Theme.CodeBlock
+	}
+	return style
+}
+
+// markCallouts splits the mmark <<N>> callouts in lines out of the tokens around them,
+// so comments such as "// <<1>>" can point at the text that explains the line.
+func markCallouts(lines [][]codeToken) [][]codeToken {
+	for i, line := range lines {
+		var marked []codeToken
+		for _, tok := range line {
+			start := 0
+			for at := 0; at < len(tok.text); {
+				open := strings.Index(tok.text[at:], "<<")
+				if open < 0 {
+					break
+				}
+				open += at
+				id, size := parser.IsCallout([]byte(tok.text[open:]))
+				if size == 0 {
+					at = open + 1
+					continue
+				}
+				if open > start {
+					marked = append(marked, codeToken{text: tok.text[start:open], kind: tok.kind})
+				}
+				marked = append(marked, codeToken{text: calloutMarker(id), kind: tokenCallout})
+				at = open + size
+				start = at
+			}
+			if start < len(tok.text) {
+				marked = append(marked, codeToken{text: tok.text[start:], kind: tok.kind})
+			}
+		}
+		lines[i] = marked
+	}
+	return lines
+}
+
+// chunkTokens splits a line of tokens into pieces no wider than width columns.
+// An empty line yields a single empty chunk so blank lines are preserved.
+func chunkTokens(line []codeToken, width int) [][]codeToken {
+	if width < 1 {
+		width = 1
+	}
+	chunks := [][]codeToken{nil}
+	used := 0
+	for _, tok := range line {
+		text := tok.text
+		for text != "" {
+			if used == width {
+				chunks = append(chunks, nil)
+				used = 0
+			}
+			head, rest := splitAtWidth(text, width-used)
+			// A wide character that does not fit in what is left of the line moves to the next one
+			if used > 0 && used+displayWidth(head) > width {
+				chunks = append(chunks, nil)
+				used = 0
+				continue
+			}
+			last := len(chunks) - 1
+			chunks[last] = append(chunks[last], codeToken{text: head, kind: tok.kind})
+			used += displayWidth(head)
+			text = rest
+		}
+	}
+	return chunks
+}
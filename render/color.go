@@ -0,0 +1,72 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/giovannirossini/markdown-render/internal/terminal"
+)
+
+// ColorMode controls whether the renderer emits ANSI escape sequences.
+type ColorMode int
+
+const (
+	// ColorAuto enables color when stdout is a terminal, honouring NO_COLOR and FORCE_COLOR.
+	ColorAuto ColorMode = iota
+	// ColorAlways emits escape sequences even when the output is not a terminal.
+	ColorAlways
+	// ColorNever renders plain text without escape sequences.
+	ColorNever
+)
+
+// String returns the flag spelling of m.
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	default:
+		return "auto"
+	}
+}
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "auto", "":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	default:
+		return ColorAuto, fmt.Errorf("unknown color mode %q (expected auto, always or never)", s)
+	}
+}
+
+// colorEnabled resolves m to a yes/no decision.
+//
+// In ColorAuto mode NO_COLOR (https://no-color.org) disables color, then
+// FORCE_COLOR enables it, and otherwise color is used only when stdout is a
+// terminal that is not TERM=dumb.
+func colorEnabled(m ColorMode) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("FORCE_COLOR"); force != "" {
+		return force != "0" && !strings.EqualFold(force, "false")
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return terminal.IsTerminal(os.Stdout)
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ColorMode
+		wantErr bool
+	}{
+		{input: "auto", want: ColorAuto},
+		{input: "", want: ColorAuto},
+		{input: "always", want: ColorAlways},
+		{input: "NEVER", want: ColorNever},
+		{input: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseColorMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColorMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseColorMode(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ColorMode
+		noColor    string
+		forceColor string
+		want       bool
+	}{
+		{name: "Always ignores NO_COLOR", mode: ColorAlways, noColor: "1", want: true},
+		{name: "Never ignores FORCE_COLOR", mode: ColorNever, forceColor: "1", want: false},
+		{name: "Auto with NO_COLOR", mode: ColorAuto, noColor: "1", forceColor: "1", want: false},
+		{name: "Auto with FORCE_COLOR", mode: ColorAuto, forceColor: "1", want: true},
+		{name: "Auto with FORCE_COLOR=0", mode: ColorAuto, forceColor: "0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+
+			if got := colorEnabled(tt.mode); got != tt.want {
+				t.Errorf("colorEnabled(%v) = %v, want %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_ColorMode(t *testing.T) {
+	markdown := "# Title\n\n**bold** and `code`"
+
+	t.Run("Never emits plain text", func(t *testing.T) {
+		result := RenderToStringWithOptions(markdown, Options{ColorMode: ColorNever})
+		if contains(result, "\x1b[") {
+			t.Errorf("ColorNever output should not contain escape sequences, got: %q", result)
+		}
+	})
+
+	t.Run("Always emits escape sequences", func(t *testing.T) {
+		result := RenderToStringWithOptions(markdown, Options{ColorMode: ColorAlways})
+		if !contains(result, "\x1b[") {
+			t.Errorf("ColorAlways output should contain escape sequences, got: %q", result)
+		}
+	})
+
+	t.Run("Does not touch fatih/color globals", func(t *testing.T) {
+		previous := color.NoColor
+		color.NoColor = true
+		defer func() { color.NoColor = previous }()
+
+		result := RenderToStringWithOptions(markdown, Options{ColorMode: ColorAlways})
+		if !contains(result, "\x1b[") {
+			t.Errorf("ColorAlways should not depend on color.NoColor, got: %q", result)
+		}
+		if !color.NoColor {
+			t.Errorf("rendering should not modify color.NoColor")
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// fenceInfo is the parsed info string of a fenced code block, e.g.
+// "go {linenos=true, hl_lines=[3,5-7]}" or the pandoc form "{.go linenos=true}".
+type fenceInfo struct {
+	language string
+	// lineNumbers overrides Options.CodeLineNumbers when set.
+	lineNumbers *bool
+	// lineStart is the number shown for the first line.
+	lineStart int
+	// highlight holds the 1-based positions of lines to emphasise.
+	highlight lineRanges
+}
+
+// lineRanges is a list of inclusive ranges of line numbers. Ranges are kept as
+// written rather than expanded, so "[1-300000000]" costs no more than "[1]".
+type lineRanges [][2]int
+
+// contains reports whether line falls in one of the ranges.
+func (l lineRanges) contains(line int) bool {
+	for _, r := range l {
+		if line >= r[0] && line <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFenceInfo splits a fence info string into the language and its attributes.
+// Unknown attributes are ignored so documents written for other renderers still display.
+func parseFenceInfo(info string) fenceInfo {
+	fi := fenceInfo{lineStart: 1}
+	info = strings.TrimSpace(info)
+
+	attrs := ""
+	if open := strings.IndexByte(info, '{'); open >= 0 {
+		end := strings.LastIndexByte(info, '}')
+		if end < open {
+			end = len(info)
+		}
+		attrs = info[open+1 : end]
+		info = info[:open]
+	}
+
+	if fields := strings.Fields(info); len(fields) > 0 {
+		fi.language = strings.TrimPrefix(fields[0], ".")
+		// Attributes may also follow the language without braces, as in "go linenos=true"
+		if attrs == "" {
+			attrs = strings.Join(fields[1:], " ")
+		}
+	}
+
+	for _, attr := range splitFenceAttributes(attrs) {
+		key, value, hasValue := strings.Cut(attr, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch {
+		case !hasValue && strings.HasPrefix(key, "."):
+			// Pandoc style class, e.g. {.go}
+			if fi.language == "" {
+				fi.language = strings.TrimPrefix(key, ".")
+			}
+		case key == "linenos":
+			// Hugo accepts "table" and "inline" as ways of turning numbers on
+			enabled := value != "false" && value != "0"
+			fi.lineNumbers = &enabled
+		case key == "linenostart":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				fi.lineStart = n
+			}
+		case key == "hl_lines":
+			fi.highlight = parseLineRanges(value)
+		}
+	}
+
+	return fi
+}
+
+// splitFenceAttributes splits "a=1, b=[2,3] c" into its attributes,
+// keeping bracketed and quoted values together.
+func splitFenceAttributes(s string) []string {
+	var attrs []string
+	var current strings.Builder
+	depth := 0
+	var quote rune
+
+	flush := func() {
+		if attr := strings.TrimSpace(current.String()); attr != "" {
+			attrs = append(attrs, attr)
+		}
+		current.Reset()
+	}
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case (r == ',' || r == ' ' || r == '\t') && depth <= 0:
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	// "key = value" written with spaces around '=' arrives as three parts
+	var merged []string
+	for i := 0; i < len(attrs); i++ {
+		switch {
+		case attrs[i] == "=" && len(merged) > 0 && i+1 < len(attrs):
+			merged[len(merged)-1] += "=" + attrs[i+1]
+			i++
+		case strings.HasSuffix(attrs[i], "=") && i+1 < len(attrs):
+			merged = append(merged, attrs[i]+attrs[i+1])
+			i++
+		default:
+			merged = append(merged, attrs[i])
+		}
+	}
+	return merged
+}
+
+// parseLineRanges parses line lists such as "[3,5-7]" or "3 5-7".
+func parseLineRanges(s string) lineRanges {
+	var ranges lineRanges
+	s = strings.Trim(s, "[]")
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		part = strings.Trim(part, `"'`)
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		if end >= start {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+	return ranges
+}
+
+// parseAttributedFence is a block parser hook for fenced code blocks whose info string
+// holds attributes after the language, such as "```go {linenos=true}". The markdown
+// parser only accepts a single word or a single {...} group after the fence and would
+// otherwise not recognise the code block at all. The hook sees each block with the
+// list and quote prefixes of its container already removed, so it only has to follow
+// the fence rules within the block.
+func parseAttributedFence(data []byte) (ast.Node, []byte, int) {
+	line, rest, _ := bytes.Cut(data, []byte("\n"))
+	indent := countLeading(string(line), ' ')
+	// Four spaces make the line part of an indented code block
+	if indent > 3 {
+		return nil, nil, 0
+	}
+	opening := string(line[indent:])
+
+	var fenceChar byte
+	fenceLen := 0
+	for _, c := range []byte{'`', '~'} {
+		if n := countLeading(opening, c); n >= 3 {
+			fenceChar, fenceLen = c, n
+			break
+		}
+	}
+	if fenceLen == 0 {
+		return nil, nil, 0
+	}
+	info := strings.TrimSpace(opening[fenceLen:])
+	// A backtick fence's info string may not contain backticks, and fences the
+	// parser understands are left to it
+	if fenceChar == '`' && strings.Contains(info, "`") || !strings.ContainsAny(info, " \t") || isBracedInfo(info) {
+		return nil, nil, 0
+	}
+
+	var literal bytes.Buffer
+	consumed := len(data)
+	for offset := len(data) - len(rest); offset < len(data); {
+		content, _, found := bytes.Cut(data[offset:], []byte("\n"))
+		next := offset + len(content)
+		if found {
+			next++
+		}
+		if isClosingFence(string(content), fenceChar, fenceLen) {
+			consumed = next
+			break
+		}
+		// Content lines lose as much indentation as the opening fence had
+		literal.Write(content[min(indent, countLeading(string(content), ' ')):])
+		literal.WriteByte('\n')
+		offset = next
+	}
+
+	block := &ast.CodeBlock{IsFenced: true, Info: []byte(info)}
+	block.Literal = literal.Bytes()
+	return block, nil, consumed
+}
+
+// isClosingFence reports whether line closes a code block opened by fenceLen fenceChars.
+func isClosingFence(line string, fenceChar byte, fenceLen int) bool {
+	indent := countLeading(line, ' ')
+	if indent > 3 {
+		return false
+	}
+	n := countLeading(line[indent:], fenceChar)
+	return n >= fenceLen && strings.TrimSpace(line[indent+n:]) == ""
+}
+
+// isBracedInfo reports whether info is a single {...} group the parser accepts as is.
+func isBracedInfo(info string) bool {
+	return strings.HasPrefix(info, "{") && strings.IndexByte(info, '}') == len(info)-1
+}
+
+// countLeading returns how many times c repeats at the start of s.
+func countLeading(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
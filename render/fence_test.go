@@ -0,0 +1,225 @@
+package render
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseFenceInfo(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name string
+		info string
+		want fenceInfo
+	}{
+		{
+			name: "Language only",
+			info: "go",
+			want: fenceInfo{language: "go", lineStart: 1},
+		},
+		{
+			name: "Normalized attributes",
+			info: "go linenos=true, hl_lines=[3,5-7]",
+			want: fenceInfo{language: "go", lineNumbers: &on, lineStart: 1, highlight: lineRanges{{3, 3}, {5, 7}}},
+		},
+		{
+			name: "Braced attributes",
+			info: "python {linenos=false linenostart=10}",
+			want: fenceInfo{language: "python", lineNumbers: &off, lineStart: 10},
+		},
+		{
+			name: "Pandoc class",
+			info: ".sh linenos=table",
+			want: fenceInfo{language: "sh", lineNumbers: &on, lineStart: 1},
+		},
+		{
+			name: "Spaces around equals and quoted ranges",
+			info: `js hl_lines = "2 4-5"`,
+			want: fenceInfo{language: "js", lineStart: 1, highlight: lineRanges{{2, 2}, {4, 5}}},
+		},
+		{
+			name: "Huge ranges are kept as ranges",
+			info: "text {hl_lines=[1-300000000, 9-2]}",
+			want: fenceInfo{language: "text", lineStart: 1, highlight: lineRanges{{1, 300000000}}},
+		},
+		{
+			name: "Unknown attributes are ignored",
+			info: `go title="main.go"`,
+			want: fenceInfo{language: "go", lineStart: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFenceInfo(tt.info); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFenceInfo(%q) = %+v, want %+v", tt.info, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributedFences(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Attributes after the language",
+			markdown: "```go {linenos=true, hl_lines=[2]}\ncode\n```",
+			contains: []string{"┌─ go ─", "│ 1 │ code"},
+			excludes: []string{"```"},
+		},
+		{
+			name:     "Blockquote and tilde fence",
+			markdown: "> ~~~~sh linenos=true\n> ls\n> ~~~~",
+			contains: []string{"┌─ sh ─", "│ 1 │ ls"},
+			excludes: []string{"~~~~"},
+		},
+		{
+			name:     "Content keeps indentation beyond the fence's",
+			markdown: "  ```go {linenos=false}\n  a\n    b\n  ```",
+			contains: []string{"│ a ", "│   b "},
+		},
+		{
+			name:     "Indented code block lines are kept as written",
+			markdown: "Text\n\n    ```go {x=1}\n    raw",
+			contains: []string{"│ ```go {x=1}", "│ raw"},
+			excludes: []string{"{go x=1}", "┌─ go"},
+		},
+		{
+			name:     "Fence-like lines inside code are kept as written",
+			markdown: "````md\n```go {x=1}\n```\n````",
+			contains: []string{"┌─ md ─", "│ ```go {x=1}", "│ ```  "},
+			excludes: []string{"{go x=1}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, ColorMode: ColorNever})
+			for _, want := range tt.contains {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestCodeBlockDecorations(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Language label",
+			markdown: "```go\nfmt.Println()\n```",
+			contains: []string{"┌─ go ─"},
+		},
+		{
+			name:     "No label without a language",
+			markdown: "```\nplain\n```",
+			excludes: []string{"┌─ "},
+		},
+		{
+			name:     "Line numbers from options",
+			markdown: "```\none\ntwo\n```",
+			opts:     Options{CodeLineNumbers: true},
+			contains: []string{"│ 1 │ one", "│ 2 │ two"},
+		},
+		{
+			name:     "Fence attributes override options",
+			markdown: "```go {linenos=false}\none\n```",
+			opts:     Options{CodeLineNumbers: true},
+			contains: []string{"│ one"},
+			excludes: []string{"1 │"},
+		},
+		{
+			name:     "Line numbers start and align",
+			markdown: "```text {linenos=true linenostart=9}\na\nb\n```",
+			contains: []string{"│  9 │ a", "│ 10 │ b"},
+		},
+		{
+			name:     "Pandoc form",
+			markdown: "```{.py linenos=true}\nx = 1\n```",
+			contains: []string{"┌─ py ─", "│ 1 │ x = 1"},
+		},
+		{
+			name:     "Tabs expand to the next tab stop",
+			markdown: "```\na\tb\nabc\tb\n日本\tb\n\t\tc\n```",
+			contains: []string{"│ a   b ", "│ abc b ", "│ 日本    b ", "│         c "},
+			excludes: []string{"\t"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Width = 40
+			tt.opts.ColorMode = ColorNever
+			result := RenderToStringWithOptions(tt.markdown, tt.opts)
+			for _, want := range tt.contains {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got != tt.opts.Width {
+				t.Errorf("Expected code box to span %d columns, got %d:\n%s", tt.opts.Width, got, result)
+			}
+		})
+	}
+}
+
+func TestCodeBlockHighlightedLines(t *testing.T) {
+	theme := DarkTheme()
+	theme.CodeHighlightLine = Style{Background: "red"}
+	opts := Options{Width: 40, Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+
+	result := RenderToStringWithOptions("```text {hl_lines=[2]}\nplain\nmarked\n```", opts)
+	var plain, marked string
+	for _, line := range strings.Split(result, "\n") {
+		switch {
+		case contains(stripANSI(line), "plain"):
+			plain = line
+		case contains(stripANSI(line), "marked"):
+			marked = line
+		}
+	}
+
+	// 41 is the SGR parameter for a red background
+	redBackground := regexp.MustCompile(`\x1b\[(\d+;)*41[;m]`)
+	if !redBackground.MatchString(marked) {
+		t.Errorf("Expected highlighted line to use the highlight background, got %q", marked)
+	}
+	if redBackground.MatchString(plain) {
+		t.Errorf("Expected other lines not to be highlighted, got %q", plain)
+	}
+}
+
+func TestCodeBlockHugeHighlightRange(t *testing.T) {
+	theme := DarkTheme()
+	theme.CodeHighlightLine = Style{Background: "red"}
+	opts := Options{Width: 40, Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+
+	// Expanding the range line by line would take minutes and gigabytes
+	result := RenderToStringWithOptions("```text {hl_lines=[1-300000000]}\nonly\n```", opts)
+	if !regexp.MustCompile(`\x1b\[(\d+;)*41[;m]`).MatchString(result) {
+		t.Errorf("Expected the only line to be highlighted, got %q", result)
+	}
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// FootnotePlacement selects where footnote definitions are printed.
+type FootnotePlacement int
+
+const (
+	// FootnotesAtEnd collects every note into one section at the end of the document.
+	FootnotesAtEnd FootnotePlacement = iota
+	// FootnotesPerSection prints the notes referenced in each top-level section
+	// (the text under the document's highest-level headings) at the end of that section.
+	FootnotesPerSection
+)
+
+// String returns the flag spelling of p.
+func (p FootnotePlacement) String() string {
+	if p == FootnotesPerSection {
+		return "section"
+	}
+	return "end"
+}
+
+// ParseFootnotePlacement parses "end" or "section".
+func ParseFootnotePlacement(s string) (FootnotePlacement, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "end", "":
+		return FootnotesAtEnd, nil
+	case "section":
+		return FootnotesPerSection, nil
+	default:
+		return FootnotesAtEnd, fmt.Errorf("unknown footnote placement %q (expected end or section)", s)
+	}
+}
+
+// footnoteMarker returns the superscript marker for the note numbered id.
+func footnoteMarker(id int) string {
+	marker, _ := scriptText(strconv.Itoa(id), true)
+	return marker
+}
+
+// noteReferenced records the note a footnote reference points to so it is printed
+// with the next notes section. Repeated references share one note.
+func (r *ANSIRenderer) noteReferenced(link *ast.Link) {
+	if link.Footnote == nil || r.notesSeen[link.NoteID] {
+		return
+	}
+	if r.notesSeen == nil {
+		r.notesSeen = make(map[int]bool)
+	}
+	r.notesSeen[link.NoteID] = true
+	r.pendingNotes = append(r.pendingNotes, link)
+}
+
+// topHeadingLevel returns the level of the highest-level heading in doc, which
+// starts a new section for FootnotesPerSection, or 0 if there are no headings.
+func topHeadingLevel(doc ast.Node) int {
+	level := 0
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if h, ok := node.(*ast.Heading); ok && entering && (level == 0 || h.Level < level) {
+			level = h.Level
+		}
+		return ast.GoToNext
+	})
+	return level
+}
+
+// writeNotes prints the notes referenced since the last notes section, in order of
+// their numbers, under a "Notes" label. Notes referenced from other notes are
+// included as they are found.
+func (r *ANSIRenderer) writeNotes(buf *bytes.Buffer) {
+	if len(r.pendingNotes) == 0 {
+		return
+	}
+
+	// Notes can reference further notes, so the list may grow while it is printed
+	for i := 0; i < len(r.pendingNotes); i++ {
+		ast.WalkFunc(r.pendingNotes[i].Footnote, func(node ast.Node, entering bool) ast.WalkStatus {
+			if link, ok := node.(*ast.Link); ok && entering && link.NoteID > 0 {
+				r.noteReferenced(link)
+			}
+			return ast.GoToNext
+		})
+	}
+	notes := r.pendingNotes
+	r.pendingNotes = nil
+
+	labelWidth := 0
+	for _, note := range notes {
+		labelWidth = max(labelWidth, displayWidth(strconv.Itoa(note.NoteID)+". "))
+	}
+
+	buf.WriteString("\n")
+	buf.WriteString(r.paint(r.opts.Theme.Footnote.merge(Style{Bold: true}), "Notes"))
+	buf.WriteString("\n")
+	for _, note := range notes {
+		label := padLeft(strconv.Itoa(note.NoteID)+". ", labelWidth)
+		lines := r.renderNote(note.Footnote, r.width()-labelWidth)
+		for i, line := range lines {
+			if i == 0 {
+				buf.WriteString(r.paint(r.opts.Theme.Footnote, label))
+			} else if line != "" {
+				buf.WriteString(strings.Repeat(" ", labelWidth))
+			}
+			buf.WriteString(line + "\n")
+		}
+	}
+	r.currentLineLen = 0
+}
+
+// renderNote renders the body of a footnote definition into lines at most width
+// columns wide. Short notes hold inline content directly, longer ones hold blocks.
+func (r *ANSIRenderer) renderNote(note ast.Node, width int) []string {
+	children := note.GetChildren()
+	if len(children) == 0 || children[0].AsContainer() == nil || isInline(children[0]) {
+		return wrapStyled(r.renderInline(note, Style{}), width)
+	}
+
+	return r.renderBlocks(children, width)
+}
+
+// isInline reports whether node is inline content rather than a block.
+func isInline(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.Emph, *ast.Strong, *ast.Del, *markNode, *ast.Link, *ast.Image, *ast.Code:
+		return true
+	}
+	return false
+}
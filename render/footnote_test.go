@@ -0,0 +1,105 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFootnotePlacement(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FootnotePlacement
+		wantErr bool
+	}{
+		{in: "", want: FootnotesAtEnd},
+		{in: "end", want: FootnotesAtEnd},
+		{in: " Section ", want: FootnotesPerSection},
+		{in: "chapter", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFootnotePlacement(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFootnotePlacement(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseFootnotePlacement(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRender_Footnotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		opts     Options
+		want     []string // in order
+		excludes []string
+	}{
+		{
+			name:     "References and notes at the end",
+			markdown: "First[^a] and second[^b] claim.\n\n[^a]: Alpha.\n[^b]: Beta.",
+			want:     []string{"First¹ and second² claim.", "Notes", "1. Alpha.", "2. Beta."},
+			excludes: []string{"[^a]", "[^b]"},
+		},
+		{
+			name:     "Repeated references share a note",
+			markdown: "One[^x], two[^x].\n\n[^x]: Shared.",
+			want:     []string{"One¹, two¹.", "1. Shared."},
+			excludes: []string{"2."},
+		},
+		{
+			name:     "Inline note",
+			markdown: "Claim^[Said *someone*.] here.",
+			want:     []string{"Claim¹ here.", "1. Said someone."},
+		},
+		{
+			name:     "Notes at the end of each section",
+			markdown: "# One\n\nA[^a].\n\n## Sub\n\nB[^b].\n\n# Two\n\nC[^c].\n\n[^a]: Note a.\n[^b]: Note b.\n[^c]: Note c.",
+			opts:     Options{FootnotePlacement: FootnotesPerSection},
+			want:     []string{"# One", "1. Note a.", "2. Note b.", "# Two", "C³", "Notes", "3. Note c."},
+		},
+		{
+			name:     "Note referenced from a note",
+			markdown: "Text[^a].\n\n[^a]: See also[^b].\n[^b]: Nested.",
+			want:     []string{"1. See also²", "2. Nested."},
+		},
+		{
+			name:     "Long notes wrap beside the label",
+			markdown: "Text[^a].\n\n[^a]: This note is long enough that it has to wrap onto a second line.",
+			opts:     Options{Width: 40},
+			want:     []string{"1. This note is long enough that it has", "\n   to wrap onto a second line."},
+		},
+		{
+			name:     "Reference in a table cell",
+			markdown: "| a |\n|---|\n| x[^a] |\n\n[^a]: Cell note.",
+			want:     []string{"x¹", "1. Cell note."},
+		},
+		{
+			name:     "No notes section without footnotes",
+			markdown: "Plain text.",
+			excludes: []string{"Notes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ColorMode = ColorNever
+			result := RenderToStringWithOptions(tt.markdown, tt.opts)
+			rest := result
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("Expected %q (in order) in output:\n%s", want, result)
+				}
+				rest = rest[i+len(want):]
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,30 @@
 package render
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 // contains checks if a string contains a substring (case-sensitive)
 // This is a helper function used across all renderer tests
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes SGR escape sequences so tests can inspect visible output
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// maxVisibleLineWidth returns the widest visible line in s, counted in terminal columns
+func maxVisibleLineWidth(s string) int {
+	widest := 0
+	for _, line := range strings.Split(stripANSI(s), "\n") {
+		if n := displayWidth(line); n > widest {
+			widest = n
+		}
+	}
+	return widest
+}
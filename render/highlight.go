@@ -0,0 +1,563 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies a piece of highlighted source code.
+type tokenKind int
+
+const (
+	tokenText tokenKind = iota
+	tokenKeyword
+	tokenType
+	tokenLiteral
+	tokenString
+	tokenNumber
+	tokenComment
+	tokenOperator
+	tokenFunction
+	tokenVariable
+	tokenKey
+	tokenInserted
+	tokenDeleted
+	tokenMeta
+	// tokenCallout is an mmark <<N>> callout, drawn as a circled number.
+	tokenCallout
+)
+
+// codeToken is a run of source text sharing one tokenKind.
+type codeToken struct {
+	text string
+	kind tokenKind
+}
+
+// language describes the lexical rules the generic highlighter needs.
+// It intentionally covers only what is needed to color common snippets;
+// it is not a parser and does not try to be exact.
+type language struct {
+	keywords     map[string]bool
+	types        map[string]bool
+	literals     map[string]bool
+	lineComments []string
+	blockComment [2]string
+	// quotes lists string delimiters; multiline lists those that may span lines.
+	quotes    string
+	multiline string
+	// tripleQuotes enables Python-style """ and ''' strings.
+	tripleQuotes bool
+	// shellVariables highlights $NAME, ${NAME} and $1.
+	shellVariables bool
+	// objectKeys highlights strings followed by a colon, as in JSON.
+	objectKeys bool
+	// caseInsensitive matches keywords regardless of case, as in SQL.
+	caseInsensitive bool
+	// identExtra lists characters other than letters, digits and '_' allowed in identifiers.
+	identExtra string
+}
+
+// words builds a lookup set from a space separated list.
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	langGo = &language{
+		keywords: words("break case chan const continue default defer else fallthrough for func go goto " +
+			"if import interface map package range return select struct switch type var"),
+		types: words("any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 " +
+			"int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr"),
+		literals:     words("true false nil iota"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		multiline:    "`",
+	}
+
+	langJSON = &language{
+		literals:   words("true false null"),
+		quotes:     "\"",
+		objectKeys: true,
+	}
+
+	langShell = &language{
+		keywords: words("if then else elif fi for while until do done case esac in function return " +
+			"local export readonly unset shift break continue exit"),
+		types:          words("echo printf cd ls cat grep sed awk read source eval exec set test"),
+		literals:       words("true false"),
+		lineComments:   []string{"#"},
+		quotes:         "\"'",
+		multiline:      "\"'",
+		shellVariables: true,
+		identExtra:     "-",
+	}
+
+	langPython = &language{
+		keywords: words("and as assert async await break class continue def del elif else except finally " +
+			"for from global if import in is lambda nonlocal not or pass raise return try while with yield"),
+		types: words("bool bytes dict float frozenset int list object set str tuple type " +
+			"len print range self super"),
+		literals:     words("True False None"),
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+		tripleQuotes: true,
+	}
+
+	langJavaScript = &language{
+		keywords: words("async await break case catch class const continue debugger default delete do else " +
+			"export extends finally for from function if import in instanceof let new of return static " +
+			"super switch this throw try typeof var void while with yield " +
+			"abstract as declare enum implements interface keyof namespace private protected public readonly type"),
+		types: words("Array Boolean Date Error Map Number Object Promise RegExp Set String Symbol " +
+			"any boolean never number string unknown void console"),
+		literals:     words("true false null undefined NaN Infinity"),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		multiline:    "`",
+		identExtra:   "$",
+	}
+
+	langSQL = &language{
+		keywords: words("add all alter and as asc begin between by case check column commit constraint create " +
+			"cross database default delete desc distinct drop else end exists foreign from full group having " +
+			"if in index inner insert into is join key left like limit not null offset on or order outer " +
+			"primary references returning right rollback select set table then transaction union unique " +
+			"update using values view when where with"),
+		types: words("bigint bit blob boolean char date datetime decimal double float int integer json " +
+			"numeric real serial smallint text time timestamp uuid varchar"),
+		literals:        words("true false"),
+		lineComments:    []string{"--"},
+		blockComment:    [2]string{"/*", "*/"},
+		quotes:          "'\"",
+		caseInsensitive: true,
+	}
+)
+
+// languages maps fence info names to their highlighting rules.
+var languages = map[string]*language{
+	"go":         langGo,
+	"golang":     langGo,
+	"json":       langJSON,
+	"jsonc":      langJSON,
+	"sh":         langShell,
+	"bash":       langShell,
+	"shell":      langShell,
+	"zsh":        langShell,
+	"console":    langShell,
+	"py":         langPython,
+	"python":     langPython,
+	"js":         langJavaScript,
+	"javascript": langJavaScript,
+	"jsx":        langJavaScript,
+	"ts":         langJavaScript,
+	"typescript": langJavaScript,
+	"tsx":        langJavaScript,
+	"sql":        langSQL,
+}
+
+// highlightCode splits code into lines of classified tokens for the fence language lang.
+// It reports false for unknown languages, which produce a single tokenText per line.
+func highlightCode(lang, code string) ([][]codeToken, bool) {
+	lang = strings.ToLower(lang)
+	var tokens []codeToken
+	switch {
+	case lang == "diff" || lang == "patch":
+		tokens = lexDiff(code)
+	case lang == "yaml" || lang == "yml":
+		tokens = lexYAML(code)
+	case languages[lang] != nil:
+		tokens = languages[lang].lex(code)
+	default:
+		return splitTokenLines([]codeToken{{text: code, kind: tokenText}}), false
+	}
+	return splitTokenLines(tokens), true
+}
+
+// splitTokenLines breaks tokens at newlines so each line can be boxed separately.
+func splitTokenLines(tokens []codeToken) [][]codeToken {
+	lines := [][]codeToken{nil}
+	for _, tok := range tokens {
+		parts := strings.Split(tok.text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				last := len(lines) - 1
+				lines[last] = append(lines[last], codeToken{text: part, kind: tok.kind})
+			}
+		}
+	}
+	return lines
+}
+
+// lexer walks source text and accumulates tokens, merging adjacent tokens of the same kind.
+type lexer struct {
+	src    []rune
+	pos    int
+	tokens []codeToken
+}
+
+func (l *lexer) emit(text string, kind tokenKind) {
+	if text == "" {
+		return
+	}
+	if n := len(l.tokens); n > 0 && l.tokens[n-1].kind == kind {
+		l.tokens[n-1].text += text
+		return
+	}
+	l.tokens = append(l.tokens, codeToken{text: text, kind: kind})
+}
+
+func (l *lexer) hasPrefix(prefix string) bool {
+	if prefix == "" {
+		return false
+	}
+	p := []rune(prefix)
+	if l.pos+len(p) > len(l.src) {
+		return false
+	}
+	for i, r := range p {
+		if l.src[l.pos+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// until advances past the next occurrence of end (or to the end of input) and returns the consumed text.
+func (l *lexer) until(end string) string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		if l.hasPrefix(end) {
+			l.pos += len([]rune(end))
+			break
+		}
+		l.pos++
+	}
+	return string(l.src[start:l.pos])
+}
+
+// untilLineEnd advances to the next newline, leaving it unconsumed.
+func (l *lexer) untilLineEnd() string {
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+	return string(l.src[start:l.pos])
+}
+
+// quoted consumes a string opened by quote, honouring backslash escapes.
+func (l *lexer) quoted(quote rune, multiline bool) string {
+	start := l.pos
+	l.pos++ // opening quote
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch {
+		case r == '\\' && quote != '`':
+			l.pos += 2
+			continue
+		case r == quote:
+			l.pos++
+			return string(l.src[start:l.pos])
+		case r == '\n' && !multiline:
+			return string(l.src[start:l.pos])
+		}
+		l.pos++
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	return string(l.src[start:l.pos])
+}
+
+// nextNonSpace returns the first rune at or after i that is not a space or tab.
+func (l *lexer) nextNonSpace(i int) rune {
+	for ; i < len(l.src); i++ {
+		if l.src[i] != ' ' && l.src[i] != '\t' {
+			return l.src[i]
+		}
+	}
+	return 0
+}
+
+const operatorChars = "+-*/%=<>!&|^~?:"
+
+// lex tokenizes code according to lang's rules.
+func (lang *language) lex(code string) []codeToken {
+	l := &lexer{src: []rune(code)}
+	isIdentRune := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(lang.identExtra, r)
+	}
+
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+
+		if l.hasPrefix(lang.blockComment[0]) {
+			start := l.pos
+			l.pos += len([]rune(lang.blockComment[0]))
+			l.until(lang.blockComment[1])
+			l.emit(string(l.src[start:l.pos]), tokenComment)
+			continue
+		}
+		if lang.matchLineComment(l) {
+			l.emit(l.untilLineEnd(), tokenComment)
+			continue
+		}
+
+		if lang.tripleQuotes && (l.hasPrefix(`"""`) || l.hasPrefix("'''")) {
+			delim := string(l.src[l.pos : l.pos+3])
+			start := l.pos
+			l.pos += 3
+			l.until(delim)
+			l.emit(string(l.src[start:l.pos]), tokenString)
+			continue
+		}
+
+		if strings.ContainsRune(lang.quotes, r) {
+			text := l.quoted(r, strings.ContainsRune(lang.multiline, r))
+			kind := tokenString
+			if lang.objectKeys && l.nextNonSpace(l.pos) == ':' {
+				kind = tokenKey
+			}
+			l.emit(text, kind)
+			continue
+		}
+
+		if lang.shellVariables && r == '$' {
+			l.emit(l.shellVariable(), tokenVariable)
+			continue
+		}
+
+		if unicode.IsDigit(r) || (r == '.' && l.pos+1 < len(l.src) && unicode.IsDigit(l.src[l.pos+1])) {
+			start := l.pos
+			for l.pos < len(l.src) && (isIdentRune(l.src[l.pos]) || l.src[l.pos] == '.') {
+				l.pos++
+			}
+			l.emit(string(l.src[start:l.pos]), tokenNumber)
+			continue
+		}
+
+		if isIdentRune(r) {
+			start := l.pos
+			for l.pos < len(l.src) && isIdentRune(l.src[l.pos]) {
+				l.pos++
+			}
+			word := string(l.src[start:l.pos])
+			l.emit(word, lang.classify(word, l.nextNonSpace(l.pos) == '('))
+			continue
+		}
+
+		if strings.ContainsRune(operatorChars, r) {
+			l.emit(string(r), tokenOperator)
+			l.pos++
+			continue
+		}
+
+		l.emit(string(r), tokenText)
+		l.pos++
+	}
+
+	return l.tokens
+}
+
+// matchLineComment reports whether a line comment starts at the lexer position.
+func (lang *language) matchLineComment(l *lexer) bool {
+	for _, marker := range lang.lineComments {
+		if !l.hasPrefix(marker) {
+			continue
+		}
+		// Shell comments only start at a word boundary, so "a#b" stays an argument
+		if marker == "#" && lang.shellVariables && l.pos > 0 && !unicode.IsSpace(l.src[l.pos-1]) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// classify returns the token kind of an identifier.
+func (lang *language) classify(word string, beforeParen bool) tokenKind {
+	key := word
+	if lang.caseInsensitive {
+		key = strings.ToLower(word)
+	}
+	switch {
+	case lang.keywords[key]:
+		return tokenKeyword
+	case lang.literals[key]:
+		return tokenLiteral
+	case lang.types[key]:
+		return tokenType
+	case beforeParen:
+		return tokenFunction
+	default:
+		return tokenText
+	}
+}
+
+// shellVariable consumes $NAME, ${...}, $(...) openers and positional parameters.
+func (l *lexer) shellVariable() string {
+	start := l.pos
+	l.pos++ // $
+	if l.pos >= len(l.src) {
+		return "$"
+	}
+	switch r := l.src[l.pos]; {
+	case r == '{':
+		l.until("}")
+	case r == '_' || unicode.IsLetter(r):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos])) {
+			l.pos++
+		}
+	case unicode.IsDigit(r) || strings.ContainsRune("@*#?$!-", r):
+		l.pos++
+	}
+	return string(l.src[start:l.pos])
+}
+
+// lexDiff classifies each line of a unified diff by its first character.
+func lexDiff(code string) []codeToken {
+	var tokens []codeToken
+	lines := strings.SplitAfter(code, "\n")
+	for _, line := range lines {
+		kind := tokenText
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+			kind = tokenMeta
+		case strings.HasPrefix(line, "+"):
+			kind = tokenInserted
+		case strings.HasPrefix(line, "-"):
+			kind = tokenDeleted
+		}
+		tokens = append(tokens, codeToken{text: line, kind: kind})
+	}
+	return tokens
+}
+
+// yamlScalars are the plain scalars YAML treats as booleans or null.
+var yamlScalars = words("true false yes no on off null ~ True False Yes No On Off Null TRUE FALSE NULL")
+
+// lexYAML highlights keys, comments, strings and scalar values line by line.
+func lexYAML(code string) []codeToken {
+	var tokens []codeToken
+	emit := func(text string, kind tokenKind) {
+		if text != "" {
+			tokens = append(tokens, codeToken{text: text, kind: kind})
+		}
+	}
+
+	for _, line := range strings.SplitAfter(code, "\n") {
+		body := strings.TrimSuffix(line, "\n")
+		newline := line[len(body):]
+
+		trimmed := strings.TrimLeft(body, " \t")
+		indent := body[:len(body)-len(trimmed)]
+		emit(indent, tokenText)
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			emit(trimmed, tokenComment)
+			emit(newline, tokenText)
+			continue
+		case trimmed == "---" || trimmed == "...":
+			emit(trimmed, tokenMeta)
+			emit(newline, tokenText)
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			emit("-", tokenOperator)
+			trimmed = trimmed[1:]
+			spaces := len(trimmed) - len(strings.TrimLeft(trimmed, " "))
+			emit(trimmed[:spaces], tokenText)
+			trimmed = trimmed[spaces:]
+		}
+
+		value := trimmed
+		if key, rest, ok := splitYAMLKey(trimmed); ok {
+			emit(key, tokenKey)
+			emit(":", tokenOperator)
+			spaces := len(rest) - len(strings.TrimLeft(rest, " "))
+			emit(rest[:spaces], tokenText)
+			value = rest[spaces:]
+		}
+
+		// Split off a trailing comment, which must be preceded by whitespace
+		comment := ""
+		if i := strings.Index(value, " #"); i >= 0 && !strings.HasPrefix(value, "\"") && !strings.HasPrefix(value, "'") {
+			value, comment = value[:i], value[i:]
+		}
+
+		emit(value, yamlValueKind(value))
+		emit(comment, tokenComment)
+		emit(newline, tokenText)
+	}
+	return tokens
+}
+
+// splitYAMLKey splits "key: value" into its key and the text after the colon.
+func splitYAMLKey(s string) (key, rest string, ok bool) {
+	if strings.HasPrefix(s, "\"") || strings.HasPrefix(s, "'") {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", "", false
+		}
+		end += 2
+		if strings.HasPrefix(s[end:], ":") {
+			return s[:end], s[end+1:], true
+		}
+		return "", "", false
+	}
+	i := strings.Index(s, ":")
+	if i <= 0 || (i+1 < len(s) && s[i+1] != ' ') || strings.Contains(s[:i], " #") {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// yamlValueKind classifies a YAML scalar value.
+func yamlValueKind(v string) tokenKind {
+	switch {
+	case v == "":
+		return tokenText
+	case strings.HasPrefix(v, "\"") || strings.HasPrefix(v, "'"):
+		return tokenString
+	case strings.HasPrefix(v, "&") || strings.HasPrefix(v, "*") || strings.HasPrefix(v, "!"):
+		return tokenVariable
+	case v == "|" || v == ">" || v == "|-" || v == ">-":
+		return tokenOperator
+	case yamlScalars[v]:
+		return tokenLiteral
+	case isYAMLNumber(v):
+		return tokenNumber
+	default:
+		return tokenString
+	}
+}
+
+// isYAMLNumber reports whether v looks like an integer or decimal number.
+func isYAMLNumber(v string) bool {
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "-"), "+")
+	if v == "" {
+		return false
+	}
+	dots := 0
+	for _, r := range v {
+		switch {
+		case r == '.':
+			dots++
+		case r == '_':
+		case !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return dots <= 1
+}
@@ -0,0 +1,154 @@
+package render
+
+import (
+	"testing"
+)
+
+// tokenKinds flattens highlighted lines into text -> kind pairs for non-text tokens.
+func tokenKinds(lines [][]codeToken) map[string]tokenKind {
+	kinds := make(map[string]tokenKind)
+	for _, line := range lines {
+		for _, tok := range line {
+			if tok.kind != tokenText {
+				kinds[tok.text] = tok.kind
+			}
+		}
+	}
+	return kinds
+}
+
+func TestHighlightCode(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		code string
+		want map[string]tokenKind
+	}{
+		{
+			name: "Go",
+			lang: "go",
+			code: "// greet\nfunc greet(name string) error {\n\tfmt.Println(`hi`, 42)\n\treturn nil /* done */\n}",
+			want: map[string]tokenKind{
+				"// greet": tokenComment, "func": tokenKeyword, "greet": tokenFunction, "string": tokenType,
+				"`hi`": tokenString, "42": tokenNumber, "nil": tokenLiteral, "/* done */": tokenComment,
+			},
+		},
+		{
+			name: "JSON",
+			lang: "json",
+			code: `{"name": "mdrender", "stars": 10, "ok": true}`,
+			want: map[string]tokenKind{`"name"`: tokenKey, `"mdrender"`: tokenString, "10": tokenNumber, "true": tokenLiteral},
+		},
+		{
+			name: "YAML",
+			lang: "yml",
+			code: "# config\nname: mdrender # inline\ncount: 3\nenabled: yes\ntags:\n  - cli\n",
+			want: map[string]tokenKind{
+				"# config": tokenComment, "name": tokenKey, "mdrender": tokenString, " # inline": tokenComment,
+				"3": tokenNumber, "yes": tokenLiteral, "cli": tokenString,
+			},
+		},
+		{
+			name: "Shell",
+			lang: "bash",
+			code: "export PATH=\"$HOME/bin\" # comment\nif [ -n ${NAME} ]; then echo $1; fi",
+			want: map[string]tokenKind{
+				"export": tokenKeyword, "\"$HOME/bin\"": tokenString, "# comment": tokenComment,
+				"${NAME}": tokenVariable, "$1": tokenVariable, "echo": tokenType, "fi": tokenKeyword,
+			},
+		},
+		{
+			name: "Python",
+			lang: "python",
+			code: "def main():\n    \"\"\"Doc\n    string\"\"\"\n    return None  # nothing",
+			want: map[string]tokenKind{"def": tokenKeyword, "main": tokenFunction, "None": tokenLiteral, "# nothing": tokenComment},
+		},
+		{
+			name: "TypeScript",
+			lang: "ts",
+			code: "const answer: number = compute(`x`);",
+			want: map[string]tokenKind{"const": tokenKeyword, "number": tokenType, "compute": tokenFunction, "`x`": tokenString},
+		},
+		{
+			name: "SQL is case insensitive",
+			lang: "sql",
+			code: "SELECT id FROM users WHERE name = 'bob' -- filter",
+			want: map[string]tokenKind{"SELECT": tokenKeyword, "FROM": tokenKeyword, "'bob'": tokenString, "-- filter": tokenComment},
+		},
+		{
+			name: "Diff",
+			lang: "diff",
+			code: "--- a/file\n+++ b/file\n@@ -1 +1 @@\n-old\n+new\n",
+			want: map[string]tokenKind{"--- a/file": tokenMeta, "@@ -1 +1 @@": tokenMeta, "-old": tokenDeleted, "+new": tokenInserted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, ok := highlightCode(tt.lang, tt.code)
+			if !ok {
+				t.Fatalf("highlightCode(%q) reported the language as unknown", tt.lang)
+			}
+			got := tokenKinds(lines)
+			for text, kind := range tt.want {
+				if got[text] != kind {
+					t.Errorf("token %q kind = %v, want %v (all tokens: %v)", text, got[text], kind, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHighlightCode_PythonDocstringSpansLines(t *testing.T) {
+	lines, _ := highlightCode("python", "x = \"\"\"a\nb\"\"\"")
+
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	last := lines[1][len(lines[1])-1]
+	if last.text != `b"""` || last.kind != tokenString {
+		t.Errorf("second docstring line = %+v, want string token %q", last, `b"""`)
+	}
+}
+
+func TestHighlightCode_UnknownLanguage(t *testing.T) {
+	lines, ok := highlightCode("brainfuck", "+++\n---")
+
+	if ok {
+		t.Errorf("highlightCode() should report unknown languages")
+	}
+	if len(lines) != 2 || lines[0][0].kind != tokenText || lines[1][0].kind != tokenText {
+		t.Errorf("unknown languages should yield plain text lines, got %+v", lines)
+	}
+}
+
+func TestRender_CodeBlock_SyntaxHighlighting(t *testing.T) {
+	theme := DarkTheme()
+	theme.Syntax.Keyword = Style{Foreground: "red"}
+	theme.CodeBlock = Style{Foreground: "green"}
+	opts := Options{Theme: theme, ColorMode: ColorAlways}
+
+	t.Run("Known language uses token styles", func(t *testing.T) {
+		result := RenderToStringWithOptions("```go\nreturn x\n```", opts)
+		if !contains(result, "\x1b[31mreturn") {
+			t.Errorf("keyword should use the syntax keyword style, got: %q", result)
+		}
+	})
+
+	t.Run("Unknown language uses the code block style", func(t *testing.T) {
+		result := RenderToStringWithOptions("```text\nreturn x\n```", opts)
+		if !contains(result, "\x1b[32mreturn x") {
+			t.Errorf("unknown languages should use the plain code block style, got: %q", result)
+		}
+	})
+
+	t.Run("Tabs keep the border aligned", func(t *testing.T) {
+		result := RenderToStringWithOptions("```go\n\tx := 1\n```", Options{Width: 40, ColorMode: ColorNever})
+		if got := maxVisibleLineWidth(result); got != 40 {
+			t.Errorf("widest line = %d, want 40", got)
+		}
+		if contains(result, "\t") {
+			t.Errorf("tabs should be expanded, got: %q", result)
+		}
+	})
+}
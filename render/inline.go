@@ -0,0 +1,105 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// renderInline renders the inline children of node on a single line, with each piece
+// styled as in running text and merged over base. It is used where content must be
+// measured and laid out as a unit, such as table cells, so it never wraps.
+func (r *ANSIRenderer) renderInline(node ast.Node, base Style) string {
+	var buf strings.Builder
+	inLink, inStrong, inEmph, inDel, inMark := r.inLink, r.inStrong, r.inEmph, r.inDel, r.inMark
+	linkURL := r.linkURL
+
+	for _, child := range node.GetChildren() {
+		ast.WalkFunc(child, func(node ast.Node, entering bool) ast.WalkStatus {
+			switch n := node.(type) {
+			case *ast.Text:
+				if entering {
+					text := strings.ReplaceAll(string(n.Literal), "\n", " ")
+					buf.WriteString(r.linked(r.paint(base.merge(r.textStyle()), text)))
+				}
+
+			case *ast.Code:
+				if entering {
+					buf.WriteString(r.linked(r.paint(base.merge(r.opts.Theme.Code), string(n.Literal))))
+				}
+
+			case *ast.Strong:
+				r.inStrong = entering
+
+			case *ast.Emph:
+				r.inEmph = entering
+
+			case *ast.Del:
+				r.inDel = entering
+
+			case *markNode:
+				r.inMark = entering
+
+			case *ast.Superscript, *ast.Subscript:
+				if entering {
+					_, super := n.(*ast.Superscript)
+					buf.WriteString(r.renderScript(string(n.AsLeaf().Literal), super, base.merge(r.textStyle())))
+				}
+
+			case *ast.Link:
+				if n.NoteID > 0 {
+					if entering {
+						buf.WriteString(r.paint(base.merge(r.opts.Theme.Footnote), footnoteMarker(n.NoteID)))
+						r.noteReferenced(n)
+					}
+					return ast.SkipChildren
+				}
+				r.inLink = entering
+				if entering {
+					r.enterLink(n.Destination)
+					break
+				}
+				r.linkURL = ""
+				switch r.linkMode() {
+				case LinkInline:
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.LinkURL), " ("+string(n.Destination)+")"))
+				case LinkFootnote:
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.LinkURL), r.linkReference(string(n.Destination))))
+				}
+
+			case *ast.Image:
+				if entering {
+					r.enterLink(n.Destination)
+					buf.WriteString(r.linked(r.paint(base.merge(r.opts.Theme.Image), "[Image: ")))
+					break
+				}
+				switch r.linkMode() {
+				case LinkInline:
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.LinkURL), " - "+string(n.Destination)))
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.Image), "]"))
+				case LinkFootnote:
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.Image), "]"))
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.LinkURL), r.linkReference(string(n.Destination))))
+				default:
+					buf.WriteString(r.linked(r.paint(base.merge(r.opts.Theme.Image), "]")))
+				}
+				r.linkURL = ""
+
+			case *ast.Callout:
+				if entering {
+					buf.WriteString(r.paint(base.merge(r.opts.Theme.Callout), calloutMarker(n.ID)))
+				}
+
+			case *ast.Softbreak, *ast.Hardbreak:
+				if entering {
+					buf.WriteString(" ")
+				}
+			}
+			return ast.GoToNext
+		})
+	}
+
+	r.inLink, r.inStrong, r.inEmph, r.inDel, r.inMark = inLink, inStrong, inEmph, inDel, inMark
+	r.linkURL = linkURL
+	return buf.String()
+}
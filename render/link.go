@@ -0,0 +1,218 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// LinkMode selects how link and image destinations are shown.
+type LinkMode int
+
+const (
+	// LinkInline follows the link text with its destination in parentheses,
+	// shortened to fit the line.
+	LinkInline LinkMode = iota
+	// LinkOSC8 makes the link text a clickable OSC 8 terminal hyperlink to the full
+	// destination and hides the parenthesised URL. Without color it falls back to LinkInline.
+	LinkOSC8
+	// LinkFootnote follows the link text with a [n] marker and lists the full
+	// destinations in a "Links" section placed according to Options.LinkPlacement.
+	LinkFootnote
+	// LinkHide shows the link text only.
+	LinkHide
+)
+
+// String returns the flag spelling of m.
+func (m LinkMode) String() string {
+	switch m {
+	case LinkOSC8:
+		return "osc8"
+	case LinkFootnote:
+		return "footnote"
+	case LinkHide:
+		return "hide"
+	default:
+		return "inline"
+	}
+}
+
+// ParseLinkMode parses "inline", "osc8", "footnote" or "hide".
+func ParseLinkMode(s string) (LinkMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "inline", "":
+		return LinkInline, nil
+	case "osc8":
+		return LinkOSC8, nil
+	case "footnote":
+		return LinkFootnote, nil
+	case "hide":
+		return LinkHide, nil
+	default:
+		return LinkInline, fmt.Errorf("unknown link mode %q (expected inline, osc8, footnote or hide)", s)
+	}
+}
+
+// LinkPlacement selects where the destinations of LinkFootnote links are listed.
+type LinkPlacement int
+
+const (
+	// LinksAtEnd lists every destination in one section at the end of the document.
+	LinksAtEnd LinkPlacement = iota
+	// LinksPerSection lists the destinations referenced in each top-level section
+	// (the text under the document's highest-level headings) at the end of that section.
+	LinksPerSection
+	// LinksPerParagraph lists the destinations referenced in each top-level block,
+	// such as a paragraph, list or table, right after it.
+	LinksPerParagraph
+)
+
+// String returns the flag spelling of p.
+func (p LinkPlacement) String() string {
+	switch p {
+	case LinksPerSection:
+		return "section"
+	case LinksPerParagraph:
+		return "paragraph"
+	default:
+		return "end"
+	}
+}
+
+// ParseLinkPlacement parses "end", "section" or "paragraph".
+func ParseLinkPlacement(s string) (LinkPlacement, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "end", "":
+		return LinksAtEnd, nil
+	case "section":
+		return LinksPerSection, nil
+	case "paragraph":
+		return LinksPerParagraph, nil
+	default:
+		return LinksAtEnd, fmt.Errorf("unknown link placement %q (expected end, section or paragraph)", s)
+	}
+}
+
+// linkRefs holds the destinations to list in the next "Links" section in LinkFootnote
+// mode, numbered in order of first reference. Nested renderers share their parent's
+// list, so the numbers run through the content listed together.
+type linkRefs struct {
+	urls   []string
+	number map[string]int
+}
+
+// linkMode returns the mode links are rendered in. Hyperlinks are escape sequences,
+// so they are only emitted along with color.
+func (r *ANSIRenderer) linkMode() LinkMode {
+	if r.opts.LinkMode == LinkOSC8 && !r.color {
+		return LinkInline
+	}
+	return r.opts.LinkMode
+}
+
+// enterLink records the destination of a link or image being entered. Its text is
+// made clickable while hyperlinks are on.
+func (r *ANSIRenderer) enterLink(dest []byte) {
+	if r.linkMode() == LinkOSC8 {
+		r.linkURL = string(dest)
+	}
+}
+
+// linkReference adds url to the next "Links" section and returns its marker.
+// Links to the same destination share a number.
+func (r *ANSIRenderer) linkReference(url string) string {
+	n, ok := r.links.number[url]
+	if !ok {
+		if r.links.number == nil {
+			r.links.number = make(map[string]int)
+		}
+		r.links.urls = append(r.links.urls, url)
+		n = len(r.links.urls)
+		r.links.number[url] = n
+	}
+	return "[" + strconv.Itoa(n) + "]"
+}
+
+// linked makes every line of text a hyperlink to the destination of the link being
+// rendered, if any. Each line is closed on its own so margins and nested content
+// prefixes added around it are not part of the link.
+func (r *ANSIRenderer) linked(text string) string {
+	if r.linkURL == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = hyperlink(r.linkURL, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hyperlink wraps text in an OSC 8 hyperlink to url. Control characters are dropped
+// from url so a destination cannot end the sequence early.
+func hyperlink(url, text string) string {
+	url = strings.Map(func(c rune) rune {
+		if c < 0x20 || c == 0x7f {
+			return -1
+		}
+		return c
+	}, url)
+	return "\x1b]8;;" + url + "\x1b\\" + text + hyperlinkEnd
+}
+
+// spaceLink puts back the spaces the source had around link, which text wrapping
+// trims, when entering and leaving it.
+func (r *ANSIRenderer) spaceLink(buf *bytes.Buffer, link ast.Node, entering bool) {
+	before, after := spacedScript(link)
+	if entering && before && r.currentLineLen > 0 && !bytes.HasSuffix(buf.Bytes(), []byte(" ")) {
+		buf.WriteString(" ")
+		r.currentLineLen++
+	}
+	if !entering && after {
+		buf.WriteString(" ")
+		r.currentLineLen++
+		r.justAddedEmphSpace = true
+	}
+}
+
+// writeLinks prints the "Links" section listing the destinations referenced in
+// LinkFootnote mode since the last one, in full so they can be copied. The next
+// section numbers its links from 1 again.
+func (r *ANSIRenderer) writeLinks(buf *bytes.Buffer) {
+	if len(r.links.urls) == 0 {
+		return
+	}
+	urls := r.links.urls
+	*r.links = linkRefs{}
+
+	labelWidth := displayWidth("[" + strconv.Itoa(len(urls)) + "] ")
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n\n")) {
+		buf.WriteString("\n")
+	}
+	buf.WriteString(r.paint(r.opts.Theme.Link.merge(Style{Bold: true}), "Links"))
+	buf.WriteString("\n")
+	for i, url := range urls {
+		label := padLeft("["+strconv.Itoa(i+1)+"] ", labelWidth)
+		buf.WriteString(r.paint(r.opts.Theme.LinkURL, label) + r.paint(r.opts.Theme.Link, url) + "\n")
+	}
+	r.currentLineLen = 0
+}
+
+// writeBlockLinks prints the "Links" section for the top-level block that has just
+// been written, set off from the block after it. Headings leave a blank line of
+// their own.
+func (r *ANSIRenderer) writeBlockLinks(buf *bytes.Buffer, block ast.Node) {
+	if len(r.links.urls) == 0 {
+		return
+	}
+	r.writeLinks(buf)
+	switch ast.GetNextNode(block).(type) {
+	case nil, *ast.Heading, *ast.Footnotes:
+	default:
+		buf.WriteString("\n")
+	}
+}
@@ -1,6 +1,7 @@
 package render
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -39,3 +40,262 @@ func TestRender_Links(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLinkMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LinkMode
+		wantErr bool
+	}{
+		{in: "", want: LinkInline},
+		{in: "inline", want: LinkInline},
+		{in: " OSC8 ", want: LinkOSC8},
+		{in: "footnote", want: LinkFootnote},
+		{in: "hide", want: LinkHide},
+		{in: "underline", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLinkMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLinkMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLinkMode(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRender_LinkModes(t *testing.T) {
+	const markdown = "See [the docs](https://example.com/docs) and ![logo](https://example.com/logo.png) now.\n\n| a |\n|---|\n| [cell](https://example.com/cell) |"
+
+	tests := []struct {
+		name     string
+		mode     LinkMode
+		want     []string // in order
+		excludes []string
+	}{
+		{
+			name: "Inline",
+			mode: LinkInline,
+			want: []string{"See the docs (https://example.com/docs) and [Image: logo - https://example.com/logo.png] now.", "cell (https://example.com/cell)"},
+		},
+		{
+			name:     "Hyperlinks fall back to inline without color",
+			mode:     LinkOSC8,
+			want:     []string{"See the docs (https://example.com/docs) and"},
+			excludes: []string{"\x1b]8;"},
+		},
+		{
+			name: "Footnote",
+			mode: LinkFootnote,
+			want: []string{
+				"See the docs[1] and [Image: logo][2] now.", "cell[3]",
+				"Links", "[1] https://example.com/docs", "[2] https://example.com/logo.png", "[3] https://example.com/cell",
+			},
+		},
+		{
+			name:     "Hide",
+			mode:     LinkHide,
+			want:     []string{"See the docs and [Image: logo] now.", "│ cell │"},
+			excludes: []string{"https://", "Links"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(markdown, Options{ColorMode: ColorNever, LinkMode: tt.mode})
+			rest := result
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("Expected %q (in order) in output:\n%s", want, result)
+				}
+				rest = rest[i+len(want):]
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_LinkHyperlinks(t *testing.T) {
+	long := "https://example.com/" + strings.Repeat("segment/", 20) + "end"
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+		excludes []string
+	}{
+		{
+			name:     "Link text links to the full destination",
+			markdown: "Read [the guide](" + long + ") first.",
+			want:     []string{"\x1b]8;;" + long + "\x1b\\", "the guide", hyperlinkEnd + " first."},
+			excludes: []string{"(https://", "..."},
+		},
+		{
+			name:     "Wrapped link text is linked on every line",
+			markdown: "Some words before [a link text long enough to wrap onto the next line](https://example.com) here.",
+			want:     []string{hyperlinkEnd + "\n\x1b]8;;https://example.com\x1b\\"},
+		},
+		{
+			name:     "Images",
+			markdown: "![logo](https://example.com/logo.png)",
+			want:     []string{"\x1b]8;;https://example.com/logo.png\x1b\\", "logo"},
+			excludes: []string{" - https://"},
+		},
+		{
+			name:     "Table cells",
+			markdown: "| a |\n|---|\n| [cell](https://example.com/cell) |",
+			want:     []string{"\x1b]8;;https://example.com/cell\x1b\\"},
+		},
+		{
+			name:     "Inline code in link text",
+			markdown: "[see `x` docs](https://example.com)",
+			want:     []string{"\x1b]8;;https://example.com\x1b\\\x1b[91m x \x1b[0m" + hyperlinkEnd},
+		},
+		{
+			name:     "Inline code in link text in table cells",
+			markdown: "| a |\n|---|\n| [see `x`](https://example.com) |",
+			want:     []string{"\x1b]8;;https://example.com\x1b\\\x1b[91mx\x1b[0m" + hyperlinkEnd},
+		},
+		{
+			name:     "Control characters are dropped from the destination",
+			markdown: "[x](<https://example.com/a\x07b>)",
+			want:     []string{"\x1b]8;;https://example.com/ab\x1b\\"},
+			excludes: []string{"\x07"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{
+				Width:        40,
+				ColorMode:    ColorAlways,
+				ColorProfile: ProfileANSI,
+				LinkMode:     LinkOSC8,
+			})
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got: %q", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got: %q", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLinkPlacement(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LinkPlacement
+		wantErr bool
+	}{
+		{in: "", want: LinksAtEnd},
+		{in: "end", want: LinksAtEnd},
+		{in: "section", want: LinksPerSection},
+		{in: " Paragraph ", want: LinksPerParagraph},
+		{in: "page", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLinkPlacement(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLinkPlacement(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLinkPlacement(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRender_LinkReferences(t *testing.T) {
+	const sections = "# One\n\nRead [a](https://a.example).\n\nThen [b](https://b.example).\n\n# Two\n\nFinally [c](https://c.example)."
+
+	tests := []struct {
+		name      string
+		markdown  string
+		placement LinkPlacement
+		want      []string // in order
+		excludes  []string
+	}{
+		{
+			name:     "Identical destinations share a number",
+			markdown: "See [one](https://example.com), [two](https://example.com) and [three](https://other.example).",
+			want:     []string{"one[1], two[1] and three[2].", "Links\n[1] https://example.com\n[2] https://other.example\n"},
+			excludes: []string{"[3]"},
+		},
+		{
+			name:     "Reference-style definitions are resolved",
+			markdown: "Read the [guide][g], the [Guide][G] again and [home].\n\n[g]: https://example.com/guide \"The guide\"\n[home]: https://example.com/",
+			want:     []string{"guide[1], the Guide[1] again and home[2].", "[1] https://example.com/guide", "[2] https://example.com/"},
+			excludes: []string{"[g]:", "The guide", "[3]"},
+		},
+		{
+			name:     "Undefined references stay as written",
+			markdown: "A [broken][nowhere] reference.",
+			want:     []string{"A [broken][nowhere] reference."},
+			excludes: []string{"Links"},
+		},
+		{
+			name:     "At the end of the document",
+			markdown: sections,
+			want:     []string{"a[1]", "b[2]", "# Two", "c[3]", "Links", "[1] https://a.example", "[3] https://c.example"},
+		},
+		{
+			name:      "At the end of each section",
+			markdown:  sections,
+			placement: LinksPerSection,
+			want:      []string{"a[1]", "b[2]", "Links\n[1] https://a.example\n[2] https://b.example\n", "# Two", "c[1]", "Links\n[1] https://c.example\n"},
+		},
+		{
+			name:      "After each paragraph",
+			markdown:  sections,
+			placement: LinksPerParagraph,
+			want: []string{
+				"a[1].\n\nLinks\n[1] https://a.example\n\n",
+				"b[1].\n\nLinks\n[1] https://b.example\n\n# Two",
+				"c[1].\n\nLinks\n[1] https://c.example\n",
+			},
+		},
+		{
+			name:      "After a list rather than each item",
+			markdown:  "- [a](https://a.example)\n- [b](https://b.example)\n\nAfter.",
+			placement: LinksPerParagraph,
+			want:      []string{"• a[1]\n• b[2]\n", "Links\n[1] https://a.example\n[2] https://b.example\n\nAfter."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{
+				ColorMode:     ColorNever,
+				LinkMode:      LinkFootnote,
+				LinkPlacement: tt.placement,
+			})
+			rest := result
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("Expected %q (in order) in output:\n%s", want, result)
+				}
+				rest = rest[i+len(want):]
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Tree guides drawn in front of nested list items when Options.ListGuides is set:
+// the branch before an item's marker and the line continuing past it to later siblings.
+const (
+	guideBranch     = "├─ "
+	guideLastBranch = "└─ "
+	guideLine       = "│  "
+	guideSpace      = "   "
+)
+
+// itemMarker is what precedes the content of a list item.
+type itemMarker struct {
+	prefix string // Painted bullet or number, and checkbox for tasks
+	width  int    // Columns the prefix occupies
+	// Tree guide before the prefix on the item's first line, and before the lines
+	// that follow; both empty without guides
+	guide, continuation string
+}
+
+// listGuides returns the tree guides for item when they are enabled. Top-level
+// items are the roots of the tree and have none.
+func (r *ANSIRenderer) listGuides(item *ast.ListItem) (guide, continuation string) {
+	if !r.opts.ListGuides || r.listLevel < 2 {
+		return "", ""
+	}
+	if ast.GetNextNode(item) == nil {
+		return guideLastBranch, guideSpace
+	}
+	return guideBranch, guideLine
+}
+
+// renderListItem renders a list item: its marker, then the content laid out in the
+// columns right of the marker, so wrapped lines and nested paragraphs, code blocks,
+// tables and quotes hang under the item's text rather than under the marker.
+// Nested lists are indented by Options.ListIndent instead, when it is set.
+//
+// The item's text keeps at least minNestedWidth columns: guides are left out when
+// they would take those, and the text starts below the marker when even the marker
+// would. Nested lists are indented no further than leaves room for their own
+// markers and guides beside that much text.
+func (r *ANSIRenderer) renderListItem(item *ast.ListItem, marker itemMarker, done bool, maxWidth int) string {
+	guideWidth := displayWidth(marker.guide)
+	if maxWidth-guideWidth-marker.width < minNestedWidth {
+		marker.guide, marker.continuation, guideWidth = "", "", 0
+	}
+	textIndent := marker.width
+	if maxWidth-guideWidth-textIndent < minNestedWidth {
+		textIndent = 0
+	}
+	nestIndent := textIndent
+	if r.opts.ListIndent > 0 {
+		nestIndent = r.opts.ListIndent
+	}
+	nestedRoom := minNestedWidth + marker.width
+	if r.opts.ListGuides {
+		nestedRoom += displayWidth(guideBranch)
+	}
+	nestIndent = max(min(nestIndent, maxWidth-guideWidth-nestedRoom), 0)
+	text := r.subRenderer(maxWidth - guideWidth - textIndent)
+	nested := r.subRenderer(maxWidth - guideWidth - nestIndent)
+	for _, sub := range []*ANSIRenderer{text, nested} {
+		// Nested lists continue the depth of this one, for their bullets and guides
+		sub.listLevel = r.listLevel
+		sub.taskDone = done
+	}
+
+	var buf strings.Builder
+	first := true
+	switch {
+	case textIndent == marker.width:
+		buf.WriteString(r.paint(r.opts.Theme.ListGuide, marker.guide) + marker.prefix)
+	case strings.TrimSpace(marker.prefix) != "":
+		// The marker takes a line of its own, without the space that set it apart
+		// from the text; a definition's indent is simply left out
+		buf.WriteString(truncateWidth(marker.prefix, marker.width-1, "") + "\n")
+		first = false
+	}
+	continuation := r.paint(r.opts.Theme.ListGuide, marker.continuation)
+	for i, child := range item.GetChildren() {
+		sub, indent := text, textIndent
+		// A nested list follows the item's text directly, other blocks are set apart
+		if _, isList := child.(*ast.List); isList {
+			sub, indent = nested, nestIndent
+		} else if i > 0 {
+			buf.WriteString(r.paint(r.opts.Theme.ListGuide, strings.TrimRight(marker.continuation, " ")) + "\n")
+		}
+		for _, line := range r.renderBlock(sub, child) {
+			switch {
+			case first:
+				first = false
+			case line == "":
+				// Keep the guide unbroken through blank lines
+				buf.WriteString(r.paint(r.opts.Theme.ListGuide, strings.TrimRight(marker.continuation, " ")))
+			default:
+				buf.WriteString(continuation + strings.Repeat(" ", indent))
+			}
+			buf.WriteString(line + "\n")
+		}
+	}
+	if first {
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// definitionIndent is the number of columns definitions are indented beneath their term.
+const definitionIndent = 4
+
+// renderDefinitionItem renders an item of a definition list: a term on its own line
+// in the term style, or a definition indented beneath the term before it.
+func (r *ANSIRenderer) renderDefinitionItem(item *ast.ListItem, maxWidth int) string {
+	if item.ListFlags&ast.ListTypeTerm == 0 {
+		indent := strings.Repeat(" ", definitionIndent)
+		return r.renderListItem(item, itemMarker{prefix: indent, width: definitionIndent}, false, maxWidth)
+	}
+
+	var buf strings.Builder
+	for _, child := range item.GetChildren() {
+		for _, line := range wrapStyled(r.renderInline(child, r.opts.Theme.DefinitionTerm), maxWidth) {
+			buf.WriteString(line + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// NumberStyle selects how the items of an ordered list are numbered.
+type NumberStyle int
+
+const (
+	// NumberDecimal numbers items 1, 2, 3.
+	NumberDecimal NumberStyle = iota
+	// NumberLowerAlpha numbers items a, b, c, continuing with aa after z.
+	NumberLowerAlpha
+	// NumberUpperAlpha numbers items A, B, C.
+	NumberUpperAlpha
+	// NumberLowerRoman numbers items i, ii, iii.
+	NumberLowerRoman
+	// NumberUpperRoman numbers items I, II, III.
+	NumberUpperRoman
+)
+
+// ParseNumberStyle parses the first item of a numbering style: "1", "a", "A", "i" or "I".
+func ParseNumberStyle(s string) (NumberStyle, error) {
+	switch strings.TrimSpace(s) {
+	case "1":
+		return NumberDecimal, nil
+	case "a":
+		return NumberLowerAlpha, nil
+	case "A":
+		return NumberUpperAlpha, nil
+	case "i":
+		return NumberLowerRoman, nil
+	case "I":
+		return NumberUpperRoman, nil
+	default:
+		return NumberDecimal, fmt.Errorf("unknown numbering style %q (expected 1, a, A, i or I)", s)
+	}
+}
+
+// format returns n in style s. Numbers that the style cannot show, such as zero in
+// letters or roman numerals past 3999, fall back to decimal.
+func (s NumberStyle) format(n int) string {
+	switch {
+	case n < 1:
+		return strconv.Itoa(n)
+	case s == NumberLowerAlpha || s == NumberUpperAlpha:
+		letters := alphaNumber(n)
+		if s == NumberUpperAlpha {
+			return strings.ToUpper(letters)
+		}
+		return letters
+	case (s == NumberLowerRoman || s == NumberUpperRoman) && n < 4000:
+		roman := romanNumber(n)
+		if s == NumberLowerRoman {
+			return strings.ToLower(roman)
+		}
+		return roman
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// alphaNumber spells n >= 1 in lowercase letters: a to z, then aa, ab and so on.
+func alphaNumber(n int) string {
+	var letters []byte
+	for ; n > 0; n = (n - 1) / 26 {
+		letters = append([]byte{byte('a' + (n-1)%26)}, letters...)
+	}
+	return string(letters)
+}
+
+// romanNumerals pairs the values of roman numerals, largest first, including the
+// subtractive forms.
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+	{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// romanNumber spells 1 <= n < 4000 in uppercase roman numerals.
+func romanNumber(n int) string {
+	var b strings.Builder
+	for _, numeral := range romanNumerals {
+		for ; n >= numeral.value; n -= numeral.value {
+			b.WriteString(numeral.symbol)
+		}
+	}
+	return b.String()
+}
+
+// listStart returns the number of the first item of an ordered list.
+func listStart(list *ast.List) int {
+	if list.Start > 0 {
+		return list.Start
+	}
+	return 1
+}
+
+// orderedMarker returns the marker of the item numbered n in an ordered list at
+// the given depth, such as "3." or "c)", using the list's own delimiter.
+func (r *ANSIRenderer) orderedMarker(list *ast.List, depth, n int) string {
+	delimiter := "."
+	if list.Delimiter == ')' {
+		delimiter = ")"
+	}
+	return r.opts.Theme.numbering(depth).format(n) + delimiter
+}
+
+// orderedMarkerWidth returns the width of the widest item marker in an ordered list,
+// which the markers are right-aligned to so the item text lines up.
+func (r *ANSIRenderer) orderedMarkerWidth(list *ast.List, depth int) int {
+	width := 0
+	start := listStart(list)
+	for i := range list.GetChildren() {
+		width = max(width, displayWidth(r.orderedMarker(list, depth, start+i)))
+	}
+	return width
+}
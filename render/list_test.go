@@ -1,7 +1,12 @@
 package render
 
 import (
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 )
 
 func TestRender_Lists(t *testing.T) {
@@ -44,3 +49,317 @@ func TestRender_Lists(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_ListHangingIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string // lines that must appear exactly
+	}{
+		{
+			name:     "Wrapped text hangs under the item text",
+			markdown: "- A list item long enough that its text has to wrap onto a second line.",
+			want:     []string{"• A list item long enough that its text", "  has to wrap onto a second line."},
+		},
+		{
+			name:     "Ordered items hang under the text after the number",
+			markdown: "1. A numbered item long enough that its text has to wrap.",
+			want:     []string{"1. A numbered item long enough that its", "   text has to wrap."},
+		},
+		{
+			name:     "Task items hang under the text after the checkbox",
+			markdown: "- [ ] A task item long enough that its text has to wrap.",
+			want:     []string{"☐ A task item long enough that its text", "  has to wrap."},
+		},
+		{
+			name:     "Nested items wrap within their own indent",
+			markdown: "- Parent\n  - Nested item that is also long enough to wrap around.",
+			want:     []string{"• Parent", "  ◦ Nested item that is also long", "    enough to wrap around."},
+		},
+		{
+			name:     "Code blocks are indented to the item text",
+			markdown: "- Item with code:\n  ```\n  x := 1\n  ```",
+			want:     []string{"• Item with code:", "  │ x := 1                             │"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Width: 40, ColorMode: ColorNever}
+			result := RenderToStringWithOptions(tt.markdown, opts)
+			lines := strings.Split(result, "\n")
+			for _, want := range tt.want {
+				if !slices.Contains(lines, want) {
+					t.Errorf("Expected line %q, got:\n%s", want, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > opts.Width {
+				t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+			}
+		})
+	}
+}
+
+func TestRender_ListItemBlocks(t *testing.T) {
+	// The parser ends a list at a table or quote, so build the item directly
+	item := &ast.ListItem{}
+	for _, src := range []string{"Item text", "| a | b |\n|---|---|\n| 1 | 2 |", "> quoted inside the item"} {
+		doc := markdown.Parse([]byte(src), newParser())
+		for _, block := range doc.GetChildren() {
+			// Detach without RemoveFromTree, which would drop the block's children
+			block.SetParent(nil)
+			ast.AppendChild(item, block)
+		}
+	}
+	list := &ast.List{}
+	ast.AppendChild(list, item)
+
+	result := NewRenderer(Options{Width: 40, ColorMode: ColorNever}).RenderNode(list)
+	lines := strings.Split(result, "\n")
+	for _, want := range []string{"• Item text", "  ┌─────┬─────┐", "  │ 1   │ 2   │", "  │ quoted inside the item"} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("Expected line %q, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestRender_DefinitionLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "Term above its definition",
+			markdown: "Apple\n:   A red fruit.",
+			want:     "Apple\n    A red fruit.\n",
+		},
+		{
+			name:     "Several definitions",
+			markdown: "Apple\n:   A fruit.\n:   A company.\n\nOrange\n:   Citrus.",
+			want:     "Apple\n    A fruit.\n\n    A company.\n\nOrange\n    Citrus.\n",
+		},
+		{
+			name:     "Wrapped definitions stay indented",
+			markdown: "Term\n:   A definition long enough that it has to wrap.",
+			want:     "Term\n    A definition long enough that it\n    has to wrap.\n",
+		},
+		{
+			name:     "Paragraphs inside a definition",
+			markdown: "Term\n:   First.\n\n    Second.",
+			want:     "Term\n    First.\n\n    Second.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, ColorMode: ColorNever})
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got %q", tt.want, result)
+			}
+			if contains(result, "•") {
+				t.Errorf("Expected no bullets in a definition list, got %q", result)
+			}
+		})
+	}
+}
+
+func TestRender_DefinitionTermStyle(t *testing.T) {
+	opts := Options{Theme: MonochromeTheme(), ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+	result := RenderToStringWithOptions("Apple\n:   A red fruit.", opts)
+	// 1 is the SGR parameter for bold text
+	if !contains(result, "\x1b[1mApple") {
+		t.Errorf("Expected the term to be bold, got %q", result)
+	}
+	if contains(result, "\x1b[1mA red") {
+		t.Errorf("Expected the definition not to be bold, got %q", result)
+	}
+}
+
+func TestRender_OrderedListNumbering(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		numbering []NumberStyle
+		want      string
+	}{
+		{
+			name:     "Start number",
+			markdown: "5. five\n6. six",
+			want:     "5. five\n6. six\n",
+		},
+		{
+			name:     "Numbers are right-aligned",
+			markdown: "9. nine\n10. ten",
+			want:     " 9. nine\n10. ten\n",
+		},
+		{
+			name:     "Parenthesis delimiter",
+			markdown: "1) one\n2) two",
+			want:     "1) one\n2) two\n",
+		},
+		{
+			name:      "Numbering styles per depth",
+			markdown:  "1. one\n   1. nested\n      1. deeper\n      2. again",
+			numbering: []NumberStyle{NumberDecimal, NumberLowerAlpha, NumberLowerRoman},
+			want:      "1. one\n   a. nested\n       i. deeper\n      ii. again\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := DarkTheme()
+			theme.Numbering = tt.numbering
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, Theme: theme, ColorMode: ColorNever})
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got %q", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestNumberStyleFormat(t *testing.T) {
+	tests := []struct {
+		style NumberStyle
+		n     int
+		want  string
+	}{
+		{NumberDecimal, 12, "12"},
+		{NumberLowerAlpha, 1, "a"},
+		{NumberLowerAlpha, 26, "z"},
+		{NumberLowerAlpha, 27, "aa"},
+		{NumberUpperAlpha, 28, "AB"},
+		{NumberLowerRoman, 4, "iv"},
+		{NumberUpperRoman, 1994, "MCMXCIV"},
+		{NumberUpperRoman, 4000, "4000"},
+		{NumberLowerAlpha, 0, "0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.style.format(tt.n); got != tt.want {
+			t.Errorf("format(%d) in style %d = %q, want %q", tt.n, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestRender_ListSpacing(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "Tight list",
+			markdown: "- one\n- two\n- three",
+			want:     "• one\n• two\n• three\n",
+		},
+		{
+			name:     "Loose list",
+			markdown: "- one\n\n- two\n\n- three",
+			want:     "• one\n\n• two\n\n• three\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, ColorMode: ColorNever})
+			if !strings.HasPrefix(result, tt.want) {
+				t.Errorf("Expected output to start with %q, got %q", tt.want, result)
+			}
+			if contains(result, "three\n\n\n") {
+				t.Errorf("Expected a single blank line after the list, got %q", result)
+			}
+		})
+	}
+}
+
+func TestRender_NestedListMarkers(t *testing.T) {
+	markdown := "- Root\n  - Child one\n    - Grandchild\n    - Grandchild two\n  - Child two\n- Root two"
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "Bullets per depth",
+			want: "• Root\n  ◦ Child one\n    ▪ Grandchild\n    ▪ Grandchild two\n  ◦ Child two\n• Root two\n",
+		},
+		{
+			name: "Indent width",
+			opts: Options{ListIndent: 4},
+			want: "• Root\n    ◦ Child one\n        ▪ Grandchild\n        ▪ Grandchild two\n    ◦ Child two\n• Root two\n",
+		},
+		{
+			name: "Tree guides",
+			opts: Options{ListGuides: true},
+			want: "• Root\n  ├─ ◦ Child one\n  │    ├─ ▪ Grandchild\n  │    └─ ▪ Grandchild two\n  └─ ◦ Child two\n• Root two\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Width = 40
+			tt.opts.ColorMode = ColorNever
+			result := RenderToStringWithOptions(markdown, tt.opts)
+			if !strings.HasPrefix(result, tt.want) {
+				t.Errorf("Expected output to start with %q, got %q", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestRender_ListIndentClamped(t *testing.T) {
+	markdown := "- one\n  - two words here\n    - three words\n      - four words"
+	opts := Options{Width: 20, ListIndent: 30, ColorMode: ColorNever}
+	result := RenderToStringWithOptions(markdown, opts)
+	if got := maxVisibleLineWidth(result); got > opts.Width {
+		t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+	}
+	words := map[string]bool{}
+	for _, word := range strings.Fields("• ◦ ▪ ‣ one two words here three four") {
+		words[word] = true
+	}
+	for _, word := range strings.Fields(result) {
+		if !words[word] {
+			t.Errorf("Expected item text to wrap at word boundaries, got %q in:\n%s", word, result)
+		}
+	}
+}
+
+func TestRender_DeepListWidth(t *testing.T) {
+	var markdown strings.Builder
+	for depth := 0; depth < 8; depth++ {
+		markdown.WriteString(strings.Repeat("  ", depth) + "- level words\n")
+	}
+
+	tests := []struct {
+		name   string
+		indent int
+	}{
+		{name: "Under the parent text"},
+		{name: "Indent width", indent: 4},
+		{name: "Indent wider than the line", indent: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Width: 20, ListIndent: tt.indent, ListGuides: true, ColorMode: ColorNever}
+			result := RenderToStringWithOptions(markdown.String(), opts)
+			if got := maxVisibleLineWidth(result); got > opts.Width {
+				t.Errorf("Expected lines to fit in %d columns, got %d:\n%s", opts.Width, got, result)
+			}
+			if !strings.Contains(result, "├─ ") && !strings.Contains(result, "└─ ") {
+				t.Errorf("Expected guides where there is room for them, got:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestRender_ListGuidesContinue(t *testing.T) {
+	markdown := "- Root\n  - A child with text long enough to wrap onto the next line\n  - Last"
+	result := RenderToStringWithOptions(markdown, Options{Width: 30, ListGuides: true, ColorMode: ColorNever})
+	want := "• Root\n  ├─ ◦ A child with text long\n  │    enough to wrap onto\n  │    the next line\n  └─ ◦ Last\n"
+	if !strings.HasPrefix(result, want) {
+		t.Errorf("Expected output to start with %q, got %q", want, result)
+	}
+}
@@ -0,0 +1,113 @@
+package render
+
+import "strings"
+
+// DefaultWidth is the line width used when Options.Width is not set.
+const DefaultWidth = 100
+
+// minContentWidth is the narrowest content area the renderer lays out into.
+// Boxes and tables need a few columns for borders and padding, so anything
+// smaller is clamped rather than producing garbled output.
+const minContentWidth = 20
+
+// minNestedWidth is the narrowest area content nested in quotes, lists and alerts
+// is laid out into. Those leave out their bars, guides, indents and borders rather
+// than squeezing the content below it.
+const minNestedWidth = 10
+
+// Options configures how markdown is laid out and styled.
+// The zero value is valid and equivalent to DefaultOptions.
+type Options struct {
+	// Width is the total number of columns a rendered line may occupy,
+	// including Margin. Zero selects DefaultWidth.
+	Width int
+	// Margin is the number of blank columns inserted before every line.
+	Margin int
+	// Theme selects the styles used for each element. Nil selects DarkTheme.
+	Theme *Theme
+	// ColorMode controls whether escape sequences are emitted. The zero value is ColorAuto.
+	ColorMode ColorMode
+	// ColorProfile selects the color depth. The zero value, ProfileAuto,
+	// detects it from COLORTERM and TERM.
+	ColorProfile ColorProfile
+	// TaskSummary prints a "3/7 done" line after every list containing task items.
+	TaskSummary bool
+	// ListIndent is the number of columns nested lists are indented by, counted from
+	// their parent item's marker. Zero lines them up with the parent item's text.
+	// It is capped so deeply nested lists keep room for their text.
+	ListIndent int
+	// ListGuides draws tree lines (├─ └─ │) that connect nested list items to their parent.
+	ListGuides bool
+	// FootnotePlacement selects where footnote definitions are printed.
+	// The zero value, FootnotesAtEnd, prints them all after the document.
+	FootnotePlacement FootnotePlacement
+	// LinkMode selects how link and image destinations are shown. The zero value,
+	// LinkInline, prints them in parentheses after the link text.
+	LinkMode LinkMode
+	// LinkPlacement selects where the destinations of LinkFootnote links are listed.
+	// The zero value, LinksAtEnd, lists them all after the document.
+	LinkPlacement LinkPlacement
+	// CodeLineNumbers shows a line-number gutter in code blocks.
+	// A fence attribute such as {linenos=false} overrides it per block.
+	CodeLineNumbers bool
+	// TableLayout selects between a grid and stacked records for tables.
+	// The zero value, TableLayoutAuto, uses records only when a grid would not be readable.
+	TableLayout TableLayout
+	// TableBorder selects the characters tables are drawn with. The zero value is BorderLight.
+	TableBorder BorderStyle
+	// TableRowSeparators draws a line between every pair of body rows.
+	TableRowSeparators bool
+	// TruncateTableCells cuts cells that do not fit their column short with "..."
+	// instead of wrapping them onto more lines.
+	TruncateTableCells bool
+	// NoWrap disables word wrapping of paragraph text. Block elements such
+	// as code boxes, tables and rules still honour Width.
+	NoWrap bool
+}
+
+// DefaultOptions returns the options used by RenderToString.
+func DefaultOptions() Options {
+	return Options{Width: DefaultWidth}
+}
+
+// withDefaults returns a copy of o with zero or invalid fields replaced by defaults.
+func (o Options) withDefaults() Options {
+	if o.Width <= 0 {
+		o.Width = DefaultWidth
+	}
+	if o.Margin < 0 {
+		o.Margin = 0
+	}
+	if o.Theme == nil {
+		o.Theme = DarkTheme()
+	}
+	return o
+}
+
+// width returns the number of columns available for content once the margin is removed.
+func (r *ANSIRenderer) width() int {
+	w := r.opts.Width - r.opts.Margin
+	// Nested content gets what is left of the clamped outer width, down to minNestedWidth
+	if r.nested {
+		return max(w, minNestedWidth)
+	}
+	if w < minContentWidth {
+		return minContentWidth
+	}
+	return w
+}
+
+// indentLines prefixes every non-empty line of s with margin spaces.
+func indentLines(s string, margin int) string {
+	if margin <= 0 || s == "" {
+		return s
+	}
+	pad := strings.Repeat(" ", margin)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderToStringWithOptions_Width(t *testing.T) {
+	longParagraph := strings.Repeat("lorem ipsum dolor sit amet ", 20)
+	markdown := longParagraph + "\n\n```\n" + strings.Repeat("x", 150) + "\n```\n\n---\n\n| A | B |\n|---|---|\n| " +
+		strings.Repeat("wide ", 30) + "| " + strings.Repeat("cell ", 30) + "|\n"
+
+	tests := []struct {
+		name  string
+		width int
+	}{
+		{name: "Narrow CI log", width: 60},
+		{name: "Classic terminal", width: 80},
+		{name: "Ultrawide", width: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(markdown, Options{Width: tt.width})
+
+			if got := maxVisibleLineWidth(result); got > tt.width {
+				t.Errorf("RenderToStringWithOptions() widest line = %d columns, want <= %d", got, tt.width)
+			}
+		})
+	}
+}
+
+func TestRenderToStringWithOptions_Defaults(t *testing.T) {
+	markdown := "# Title\n\nSome **bold** text"
+
+	if got, want := RenderToStringWithOptions(markdown, Options{}), RenderToString(markdown); got != want {
+		t.Errorf("zero Options should match RenderToString()\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestRenderToStringWithOptions_Margin(t *testing.T) {
+	result := stripANSI(RenderToStringWithOptions("Hello margin\n\n---", Options{Width: 40, Margin: 4}))
+
+	for _, line := range strings.Split(result, "\n") {
+		if line != "" && !strings.HasPrefix(line, "    ") {
+			t.Errorf("line %q should start with a 4 column margin", line)
+		}
+	}
+	if got := maxVisibleLineWidth(result); got > 40 {
+		t.Errorf("widest line = %d columns, want <= 40", got)
+	}
+}
+
+func TestRenderToStringWithOptions_NoWrap(t *testing.T) {
+	paragraph := strings.TrimSpace(strings.Repeat("word ", 40))
+
+	result := stripANSI(RenderToStringWithOptions(paragraph, Options{Width: 40, NoWrap: true}))
+
+	if !contains(result, paragraph) {
+		t.Errorf("NoWrap output should keep the paragraph on one line, got: %q", result)
+	}
+}
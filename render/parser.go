@@ -0,0 +1,73 @@
+package render
+
+import (
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// parserExtensions are the markdown extensions the renderer understands:
+// GitHub flavoured markdown plus ordered lists that start at any number, [^1]
+// footnotes and 2^10^ and H~2~O style super- and subscripts.
+const parserExtensions = parser.CommonExtensions | parser.OrderedListStart | parser.Footnotes | parser.SuperSubscript
+
+// markNode is ==highlighted== text. The parser has no node for it, so it is
+// recognised by parseMark.
+type markNode struct {
+	ast.Container
+}
+
+// newParser returns a parser with parserExtensions enabled, ==mark== support,
+// literal <<N>> callouts, mmark asides, code fences with attributes and table rows
+// longer than the header.
+// A parser holds state for one document, so a new one is needed per parse.
+func newParser() *parser.Parser {
+	p := parser.NewWithExtensions(parserExtensions)
+	p.RegisterInline('=', parseMark)
+	keepCalloutText(p)
+
+	hooks := []parser.BlockFunc{
+		parseAttributedFence,
+		parseAside,
+		func(data []byte) (ast.Node, []byte, int) { return widenTableHeader(p, data) },
+	}
+	p.Opts.ParserHook = func(data []byte) (ast.Node, []byte, int) {
+		for _, hook := range hooks {
+			if node, content, size := hook(data); size > 0 {
+				return node, content, size
+			}
+		}
+		return nil, nil, 0
+	}
+	return p
+}
+
+// parseMark parses ==text== at data[offset:] into a markNode. Like ~~strikethrough~~,
+// the text must not start or end with a space, and runs of three or more '=' are
+// left alone.
+func parseMark(p *parser.Parser, data []byte, offset int) (int, ast.Node) {
+	if offset > 0 && data[offset-1] == '=' {
+		return 0, nil
+	}
+	data = data[offset:]
+	if len(data) < 5 || data[1] != '=' || data[2] == '=' || isSpaceByte(data[2]) {
+		return 0, nil
+	}
+
+	for i := 3; i+1 < len(data); i++ {
+		if data[i] != '=' || data[i+1] != '=' {
+			continue
+		}
+		if isSpaceByte(data[i-1]) || i+2 < len(data) && data[i+2] == '=' {
+			return 0, nil
+		}
+		node := &markNode{}
+		p.Inline(node, data[2:i])
+		return i + 2, node
+	}
+	return 0, nil
+}
+
+// isSpaceByte reports whether c is ASCII whitespace.
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
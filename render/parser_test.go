@@ -0,0 +1,84 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+func TestParseMark(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string // text of the mark node, empty for none
+	}{
+		{name: "Simple mark", markdown: "a ==b c== d", want: "b c"},
+		{name: "Nested emphasis", markdown: "==a *b*==", want: "a b"},
+		{name: "Space after opening", markdown: "a == b== c"},
+		{name: "Space before closing", markdown: "a ==b == c"},
+		{name: "Comparison", markdown: "if a == b"},
+		{name: "Three equals", markdown: "a ===b=== c"},
+		{name: "Unclosed", markdown: "==open"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := markdown.Parse([]byte(tt.markdown), newParser())
+			var got strings.Builder
+			ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+				if _, ok := node.(*markNode); ok && entering {
+					ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+						if leaf := child.AsLeaf(); leaf != nil && entering {
+							got.Write(leaf.Literal)
+						}
+						return ast.GoToNext
+					})
+				}
+				return ast.GoToNext
+			})
+			if got.String() != tt.want {
+				t.Errorf("mark text = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_StrikethroughAndMark(t *testing.T) {
+	theme := MonochromeTheme()
+	opts := Options{Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		// 9 is the SGR parameter for crossed-out text
+		{name: "Strikethrough", markdown: "keep ~~removed~~ text", want: "\x1b[9mremoved"},
+		// 1 and 4 are bold and underline, the monochrome highlight
+		{name: "Mark", markdown: "keep ==marked== text", want: "\x1b[1;4mmarked"},
+		{name: "Strikethrough in a table", markdown: "| a |\n|---|\n| ~~old~~ |", want: "\x1b[9mold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, opts)
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.want, result)
+			}
+		})
+	}
+}
+
+// BenchmarkParseLongDocument parses a long document of paragraphs, some with pipes but
+// none of them tables.
+// The block hooks run at the start of every block, so any of them reading the rest
+// of the document each time makes this quadratic.
+func BenchmarkParseLongDocument(b *testing.B) {
+	doc := []byte(strings.Repeat("A paragraph of plain words | with a pipe in it.\n\n", 16000))
+	b.SetBytes(int64(len(doc)))
+	for i := 0; i < b.N; i++ {
+		markdown.Parse(doc, newParser())
+	}
+}
@@ -0,0 +1,261 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ColorProfile is the color depth a terminal supports.
+type ColorProfile int
+
+const (
+	// ProfileAuto detects the profile from COLORTERM and TERM.
+	ProfileAuto ColorProfile = iota
+	// ProfileTrueColor emits 24-bit RGB sequences.
+	ProfileTrueColor
+	// ProfileANSI256 emits xterm 256-color palette sequences.
+	ProfileANSI256
+	// ProfileANSI emits only the 16 basic ANSI colors.
+	ProfileANSI
+)
+
+// String returns the flag spelling of p.
+func (p ColorProfile) String() string {
+	switch p {
+	case ProfileTrueColor:
+		return "truecolor"
+	case ProfileANSI256:
+		return "256"
+	case ProfileANSI:
+		return "16"
+	default:
+		return "auto"
+	}
+}
+
+// ParseColorProfile parses "auto", "truecolor" (or "24bit"), "256" or "16" (or "ansi").
+func ParseColorProfile(s string) (ColorProfile, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "auto", "":
+		return ProfileAuto, nil
+	case "truecolor", "24bit":
+		return ProfileTrueColor, nil
+	case "256":
+		return ProfileANSI256, nil
+	case "16", "ansi":
+		return ProfileANSI, nil
+	default:
+		return ProfileAuto, fmt.Errorf("unknown color profile %q (expected auto, truecolor, 256 or 16)", s)
+	}
+}
+
+// DetectColorProfile guesses the terminal's color depth from COLORTERM and TERM.
+func DetectColorProfile() ColorProfile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case strings.HasSuffix(term, "-direct"):
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return ProfileANSI256
+	default:
+		return ProfileANSI
+	}
+}
+
+// rgb is a 24-bit color.
+type rgb struct {
+	r, g, b int
+}
+
+// ansiHues lists the chromatic basic colors by hue, 60 degrees apart starting at red.
+var ansiHues = []struct {
+	normal, bright color.Attribute
+}{
+	{color.FgRed, color.FgHiRed},
+	{color.FgYellow, color.FgHiYellow},
+	{color.FgGreen, color.FgHiGreen},
+	{color.FgCyan, color.FgHiCyan},
+	{color.FgBlue, color.FgHiBlue},
+	{color.FgMagenta, color.FgHiMagenta},
+}
+
+const (
+	// minANSIChroma is the channel spread below which a color is treated as gray.
+	minANSIChroma = 40
+	// minBrightValue is the strongest-channel value from which bright variants are used.
+	minBrightValue = 220
+)
+
+// cubeLevels are the channel intensities of the xterm 6x6x6 color cube.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// parseHex decodes "#rgb" or "#rrggbb".
+func parseHex(s string) (rgb, bool) {
+	if !strings.HasPrefix(s, "#") {
+		return rgb{}, false
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return rgb{}, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgb{}, false
+	}
+	return rgb{int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)}, true
+}
+
+// valid reports whether c is empty, a known color name or a hex color.
+func (c Color) valid() bool {
+	if c == "" {
+		return true
+	}
+	if _, ok := namedColors[c]; ok {
+		return true
+	}
+	_, ok := parseHex(string(c))
+	return ok
+}
+
+// attributes returns the SGR parameters selecting c as a foreground (or background) color under profile.
+// Named colors always map to the basic palette so they follow the user's terminal scheme;
+// hex colors are down-sampled to the nearest color the profile can show.
+func (c Color) attributes(profile ColorProfile, background bool) []color.Attribute {
+	offset := color.Attribute(0)
+	if background {
+		offset = 10
+	}
+
+	if attr, ok := namedColors[c]; ok {
+		return []color.Attribute{attr + offset}
+	}
+
+	v, ok := parseHex(string(c))
+	if !ok {
+		return nil
+	}
+
+	// 38 and 48 introduce extended foreground and background colors
+	extended := 38 + offset
+	switch profile {
+	case ProfileTrueColor:
+		return []color.Attribute{extended, 2, color.Attribute(v.r), color.Attribute(v.g), color.Attribute(v.b)}
+	case ProfileANSI256:
+		return []color.Attribute{extended, 5, color.Attribute(nearest256(v))}
+	default:
+		return []color.Attribute{nearestANSI(v) + offset}
+	}
+}
+
+// nearest256 returns the xterm-256 palette index closest to v,
+// choosing between the 6x6x6 color cube and the 24-step grayscale ramp.
+func nearest256(v rgb) int {
+	ri, gi, bi := nearestCubeLevel(v.r), nearestCubeLevel(v.g), nearestCubeLevel(v.b)
+	cube := rgb{cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]}
+	cubeIndex := 16 + 36*ri + 6*gi + bi
+
+	// Grayscale ramp 232-255 covers 8, 18, ..., 238
+	avg := (v.r + v.g + v.b) / 3
+	grayStep := (avg - 8 + 5) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	}
+	if grayStep > 23 {
+		grayStep = 23
+	}
+	grayLevel := 8 + 10*grayStep
+	gray := rgb{grayLevel, grayLevel, grayLevel}
+
+	if colorDistance(v, gray) < colorDistance(v, cube) {
+		return 232 + grayStep
+	}
+	return cubeIndex
+}
+
+// nearestCubeLevel returns the index of the cube intensity closest to channel value x.
+func nearestCubeLevel(x int) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(x-level) < abs(x-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// nearestANSI returns the foreground attribute of the basic color closest to v.
+// Terminals disagree wildly on the exact RGB values of the basic colors, so
+// rather than measuring distances to one palette the color is classified by
+// hue, with low-saturation colors mapped onto the four grays by brightness.
+func nearestANSI(v rgb) color.Attribute {
+	hi := max(v.r, v.g, v.b)
+	lo := min(v.r, v.g, v.b)
+
+	if hi-lo < minANSIChroma {
+		switch avg := (v.r + v.g + v.b) / 3; {
+		case avg < 64:
+			return color.FgBlack
+		case avg < 160:
+			return color.FgHiBlack
+		case avg < 235:
+			return color.FgWhite
+		default:
+			return color.FgHiWhite
+		}
+	}
+
+	sector := (hue(v) + 30) / 60 % len(ansiHues)
+	if hi >= minBrightValue {
+		return ansiHues[sector].bright
+	}
+	return ansiHues[sector].normal
+}
+
+// hue returns the HSV hue of v in degrees, in the range [0, 360).
+func hue(v rgb) int {
+	hi := max(v.r, v.g, v.b)
+	c := hi - min(v.r, v.g, v.b)
+	if c == 0 {
+		return 0
+	}
+	var h float64
+	switch hi {
+	case v.r:
+		h = float64(v.g-v.b) / float64(c)
+	case v.g:
+		h = float64(v.b-v.r)/float64(c) + 2
+	default:
+		h = float64(v.r-v.g)/float64(c) + 4
+	}
+	deg := int(h * 60)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// colorDistance is the squared "redmean" distance, a cheap approximation of perceived difference.
+func colorDistance(a, b rgb) int {
+	rmean := (a.r + b.r) / 2
+	dr, dg, db := a.r-b.r, a.g-b.g, a.b-b.b
+	return ((512+rmean)*dr*dr)>>8 + 4*dg*dg + ((767-rmean)*db*db)>>8
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseColorProfile(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ColorProfile
+		wantErr bool
+	}{
+		{input: "auto", want: ProfileAuto},
+		{input: "truecolor", want: ProfileTrueColor},
+		{input: "24bit", want: ProfileTrueColor},
+		{input: "256", want: ProfileANSI256},
+		{input: "16", want: ProfileANSI},
+		{input: "ANSI", want: ProfileANSI},
+		{input: "88", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseColorProfile(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColorProfile(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseColorProfile(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		colorTerm string
+		term      string
+		want      ColorProfile
+	}{
+		{name: "COLORTERM truecolor", colorTerm: "truecolor", term: "xterm", want: ProfileTrueColor},
+		{name: "COLORTERM 24bit", colorTerm: "24bit", term: "screen", want: ProfileTrueColor},
+		{name: "Direct color terminfo", term: "xterm-direct", want: ProfileTrueColor},
+		{name: "256 color terminfo", term: "xterm-256color", want: ProfileANSI256},
+		{name: "Basic terminal", term: "xterm", want: ProfileANSI},
+		{name: "Nothing set", want: ProfileANSI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", tt.colorTerm)
+			t.Setenv("TERM", tt.term)
+
+			if got := DetectColorProfile(); got != tt.want {
+				t.Errorf("DetectColorProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColor_Attributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		color      Color
+		profile    ColorProfile
+		background bool
+		want       []color.Attribute
+	}{
+		{name: "Named color ignores profile", color: "blue", profile: ProfileTrueColor, want: []color.Attribute{color.FgBlue}},
+		{name: "Named background", color: "red", profile: ProfileANSI, background: true, want: []color.Attribute{color.BgRed}},
+		{name: "Hex truecolor", color: "#5f87ff", profile: ProfileTrueColor, want: []color.Attribute{38, 2, 0x5f, 0x87, 0xff}},
+		{name: "Short hex truecolor background", color: "#f80", profile: ProfileTrueColor, background: true, want: []color.Attribute{48, 2, 0xff, 0x88, 0x00}},
+		{name: "Hex on cube", color: "#5f87ff", profile: ProfileANSI256, want: []color.Attribute{38, 5, 69}},
+		{name: "Hex gray ramp", color: "#6c6c6c", profile: ProfileANSI256, want: []color.Attribute{38, 5, 242}},
+		{name: "Hex to basic red", color: "#ff5f5f", profile: ProfileANSI, want: []color.Attribute{color.FgHiRed}},
+		{name: "Hex to basic dark blue", color: "#00005f", profile: ProfileANSI, want: []color.Attribute{color.FgBlue}},
+		{name: "Hex to basic orange", color: "#ffaf00", profile: ProfileANSI, want: []color.Attribute{color.FgHiYellow}},
+		{name: "Hex to basic gray", color: "#6c6c6c", profile: ProfileANSI, want: []color.Attribute{color.FgHiBlack}},
+		{name: "Hex to basic background", color: "#000000", profile: ProfileANSI, background: true, want: []color.Attribute{color.BgBlack}},
+		{name: "Empty", color: "", profile: ProfileTrueColor, want: nil},
+		{name: "Invalid hex", color: "#12345", profile: ProfileTrueColor, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.attributes(tt.profile, tt.background); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Color(%q).attributes(%v, %v) = %v, want %v", tt.color, tt.profile, tt.background, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_ColorProfile(t *testing.T) {
+	theme := DarkTheme()
+	theme.Code = Style{Foreground: "#ff5f00"}
+
+	tests := []struct {
+		name    string
+		profile ColorProfile
+		want    string
+	}{
+		{name: "Truecolor", profile: ProfileTrueColor, want: "\x1b[38;2;255;95;0m"},
+		{name: "256 colors", profile: ProfileANSI256, want: "\x1b[38;5;202m"},
+		{name: "16 colors", profile: ProfileANSI, want: "\x1b[91m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions("`code`", Options{Theme: theme, ColorMode: ColorAlways, ColorProfile: tt.profile})
+			if !contains(result, tt.want) {
+				t.Errorf("output should contain %q, got: %q", tt.want, result)
+			}
+		})
+	}
+}
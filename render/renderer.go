@@ -3,165 +3,21 @@ package render
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/ast"
 )
 
-const maxLineWidth = 100
-
-// calculateTableColumnWidths calculates the width needed for each column
-func (r *ANSIRenderer) calculateTableColumnWidths() {
-	if len(r.tableRows) == 0 {
-		return
-	}
-
-	numCols := len(r.tableRows[0])
-	r.tableColumnWidths = make([]int, numCols)
-
-	// Find max width for each column
-	for _, row := range r.tableRows {
-		for i, cell := range row {
-			if i < len(r.tableColumnWidths) {
-				cellLen := len(cell)
-				if cellLen > r.tableColumnWidths[i] {
-					r.tableColumnWidths[i] = cellLen
-				}
-			}
-		}
-	}
-
-	// Ensure minimum width of 3 for each column
-	for i := range r.tableColumnWidths {
-		if r.tableColumnWidths[i] < 3 {
-			r.tableColumnWidths[i] = 3
-		}
-	}
-}
-
-// renderTable renders the collected table data
-func (r *ANSIRenderer) renderTable() string {
-	if len(r.tableRows) == 0 {
-		return ""
-	}
-
-	var result strings.Builder
-	r.calculateTableColumnWidths()
-
-	// Calculate total table width
-	totalWidth := 1 // Start with left border
-	for _, width := range r.tableColumnWidths {
-		totalWidth += width + 3 // cell width + 2 spaces padding + 1 border
-	}
-
-	// Limit table width to maxLineWidth
-	if totalWidth > maxLineWidth {
-		// Scale down columns proportionally
-		scale := float64(maxLineWidth-1-len(r.tableColumnWidths)*3) / float64(totalWidth-1-len(r.tableColumnWidths)*3)
-		for i := range r.tableColumnWidths {
-			r.tableColumnWidths[i] = int(float64(r.tableColumnWidths[i]) * scale)
-			if r.tableColumnWidths[i] < 3 {
-				r.tableColumnWidths[i] = 3
-			}
-		}
-	}
-
-	// Render top border
-	result.WriteString("\n")
-	result.WriteString(color.New(color.FgHiBlack).Sprint("┌"))
-	for i, width := range r.tableColumnWidths {
-		if i > 0 {
-			result.WriteString(color.New(color.FgHiBlack).Sprint("┬"))
-		}
-		result.WriteString(color.New(color.FgHiBlack).Sprint(strings.Repeat("─", width+2)))
-	}
-	result.WriteString(color.New(color.FgHiBlack).Sprint("┐\n"))
-
-	// Render rows
-	for rowIdx, row := range r.tableRows {
-		// Render cell row
-		result.WriteString(color.New(color.FgHiBlack).Sprint("│"))
-		for colIdx, cell := range row {
-			cellContent := cell
-			if colIdx >= len(r.tableColumnWidths) {
-				continue
-			}
-			colWidth := r.tableColumnWidths[colIdx]
-
-			// Truncate cell content if too long
-			if len(cellContent) > colWidth {
-				cellContent = cellContent[:colWidth-3] + "..."
-			}
-
-			// Apply alignment
-			alignment := ast.TableAlignmentLeft
-			if colIdx < len(r.tableAlignments) {
-				alignment = r.tableAlignments[colIdx]
-			}
-
-			var paddedCell string
-			switch alignment {
-			case ast.TableAlignmentCenter:
-				padding := colWidth - len(cellContent)
-				leftPad := padding / 2
-				rightPad := padding - leftPad
-				paddedCell = strings.Repeat(" ", leftPad) + cellContent + strings.Repeat(" ", rightPad)
-			case ast.TableAlignmentRight:
-				paddedCell = fmt.Sprintf("%*s", colWidth, cellContent)
-			default: // Left alignment
-				paddedCell = fmt.Sprintf("%-*s", colWidth, cellContent)
-			}
-
-			// Apply header styling for first row
-			if rowIdx == 0 {
-				result.WriteString(" ")
-				result.WriteString(color.New(color.FgWhite, color.Bold).Sprint(paddedCell))
-				result.WriteString(" ")
-			} else {
-				result.WriteString(" ")
-				result.WriteString(paddedCell)
-				result.WriteString(" ")
-			}
-			result.WriteString(color.New(color.FgHiBlack).Sprint("│"))
-		}
-		result.WriteString("\n")
-
-		// Render separator after header
-		if rowIdx == 0 {
-			result.WriteString(color.New(color.FgHiBlack).Sprint("├"))
-			for i, width := range r.tableColumnWidths {
-				if i > 0 {
-					result.WriteString(color.New(color.FgHiBlack).Sprint("┼"))
-				}
-				result.WriteString(color.New(color.FgHiBlack).Sprint(strings.Repeat("─", width+2)))
-			}
-			result.WriteString(color.New(color.FgHiBlack).Sprint("┤\n"))
-		}
-	}
-
-	// Render bottom border
-	result.WriteString(color.New(color.FgHiBlack).Sprint("└"))
-	for i, width := range r.tableColumnWidths {
-		if i > 0 {
-			result.WriteString(color.New(color.FgHiBlack).Sprint("┴"))
-		}
-		result.WriteString(color.New(color.FgHiBlack).Sprint(strings.Repeat("─", width+2)))
-	}
-	result.WriteString(color.New(color.FgHiBlack).Sprint("┘\n"))
-
-	return result.String()
-}
-
-// wrapText wraps text to maxLineWidth characters, breaking at word boundaries when possible
-func wrapText(text string) string {
-	wrapped, _ := wrapTextWithOffset(text, 0)
+// wrapText wraps text to maxWidth columns, breaking at word boundaries when possible
+func wrapText(text string, maxWidth int) string {
+	wrapped, _ := wrapTextWithOffset(text, 0, maxWidth)
 	return wrapped
 }
 
-// wrapTextWithOffset wraps text to maxLineWidth characters, considering current line offset
-func wrapTextWithOffset(text string, currentOffset int) (string, int) {
+// wrapTextWithOffset wraps text to maxWidth columns, considering current line offset
+func wrapTextWithOffset(text string, currentOffset int, maxWidth int) (string, int) {
 	if len(text) == 0 {
 		return text, currentOffset
 	}
@@ -172,14 +28,15 @@ func wrapTextWithOffset(text string, currentOffset int) (string, int) {
 	lineLength := currentOffset
 
 	// If we're already at or over the limit, start on a new line
-	if lineLength >= maxLineWidth && len(words) > 0 {
+	if lineLength >= maxWidth && len(words) > 0 {
 		result.WriteString("\n")
 		lineLength = 0
 	}
 
 	for _, word := range words {
-		// Handle words that are longer than maxLineWidth by breaking them
-		if len(word) > maxLineWidth {
+		wordWidth := displayWidth(word)
+		// Handle words that are longer than maxWidth by breaking them
+		if wordWidth > maxWidth {
 			// Finish current line if it has content
 			if currentLine != "" {
 				result.WriteString(currentLine)
@@ -188,15 +45,16 @@ func wrapTextWithOffset(text string, currentOffset int) (string, int) {
 				lineLength = 0
 			}
 			// Break the long word into chunks
-			for len(word) > maxLineWidth {
-				result.WriteString(word[:maxLineWidth])
+			for displayWidth(word) > maxWidth {
+				var chunk string
+				chunk, word = splitAtWidth(word, maxWidth)
+				result.WriteString(chunk)
 				result.WriteString("\n")
-				word = word[maxLineWidth:]
 				lineLength = 0
 			}
 			if len(word) > 0 {
 				currentLine = word
-				lineLength = len(word)
+				lineLength = displayWidth(word)
 			}
 			continue
 		}
@@ -206,26 +64,26 @@ func wrapTextWithOffset(text string, currentOffset int) (string, int) {
 		if currentLine != "" {
 			spaceNeeded = 1 // space between words
 		}
-		// Wrap if adding this word would exceed or reach exactly the limit (since maxLineWidth is the maximum)
-		if lineLength+len(word)+spaceNeeded >= maxLineWidth {
+		// Wrap if adding this word would exceed or reach exactly the limit (since maxWidth is the maximum)
+		if lineLength+wordWidth+spaceNeeded >= maxWidth {
 			if currentLine != "" {
 				result.WriteString(currentLine)
 				result.WriteString("\n")
 				currentLine = word
-				lineLength = len(word)
+				lineLength = wordWidth
 			} else {
 				// Current line is empty but we're at/over the limit, wrap to new line
 				result.WriteString("\n")
 				currentLine = word
-				lineLength = len(word)
+				lineLength = wordWidth
 			}
 		} else {
 			if currentLine != "" {
 				currentLine += " " + word
-				lineLength += spaceNeeded + len(word)
+				lineLength += spaceNeeded + wordWidth
 			} else {
 				currentLine = word
-				lineLength += len(word)
+				lineLength += wordWidth
 			}
 		}
 	}
@@ -237,36 +95,18 @@ func wrapTextWithOffset(text string, currentOffset int) (string, int) {
 	return result.String(), lineLength
 }
 
-// RenderToString renders markdown content with ANSI colors and returns the string
+// RenderToString renders markdown content with ANSI colors using DefaultOptions and returns the string
 func RenderToString(content string) string {
-	// Force color output even when piped (for use with less -R)
-	color.NoColor = false
+	return RenderToStringWithOptions(content, DefaultOptions())
+}
 
+// RenderToStringWithOptions renders markdown content with ANSI colors according to opts and returns the string
+func RenderToStringWithOptions(content string, opts Options) string {
 	// Parse markdown
-	doc := markdown.Parse([]byte(content), nil)
-
-	// Create renderer
-	renderer := &ANSIRenderer{
-		listLevel:          0,
-		listIndex:          make(map[int]int),
-		inCodeBlock:        false,
-		inEmph:             false,
-		inStrong:           false,
-		inHeading:          0,
-		currentLineLen:     0,
-		justAddedEmphSpace: false,
-		inTable:            false,
-		tableColumnWidths:  nil,
-		tableCurrentRow:    nil,
-		tableRows:          nil,
-		tableAlignments:    nil,
-		isTableHeader:      false,
-		inTableCell:        false,
-		tableCellBuffer:    nil,
-	}
+	doc := markdown.Parse([]byte(content), newParser())
 
 	// Render and return
-	return renderer.RenderNode(doc)
+	return NewRenderer(opts).RenderNode(doc)
 }
 
 // Render renders markdown content with ANSI colors and prints to stdout
@@ -275,64 +115,194 @@ func Render(content string) {
 	fmt.Print(output)
 }
 
+// NewRenderer creates an ANSIRenderer configured by opts.
+// Zero-valued fields in opts fall back to their defaults.
+func NewRenderer(opts Options) *ANSIRenderer {
+	opts = opts.withDefaults()
+	profile := opts.ColorProfile
+	if profile == ProfileAuto {
+		profile = DetectColorProfile()
+	}
+	return &ANSIRenderer{
+		opts:            opts,
+		color:           colorEnabled(opts.ColorMode),
+		profile:         profile,
+		listIndex:       make(map[int]int),
+		listMarkerWidth: make(map[int]int),
+		links:           &linkRefs{},
+	}
+}
+
 // ANSIRenderer renders markdown to ANSI colored terminal output
 type ANSIRenderer struct {
+	opts               Options
+	color              bool         // Whether escape sequences are emitted, resolved from opts.ColorMode
+	profile            ColorProfile // Color depth, resolved from opts.ColorProfile
+	nested             bool         // Whether this renders content nested in another renderer's output
 	listLevel          int
 	listIndex          map[int]int
+	listMarkerWidth    map[int]int // Width of the widest number in the ordered list at each level
 	inCodeBlock        bool
 	inEmph             bool
 	inStrong           bool
+	inLink             bool
+	inDel              bool
+	inMark             bool
 	inHeading          int  // Track which heading level we're in (0 = not in heading)
 	currentLineLen     int  // Track current visual line length (excluding ANSI codes)
 	justAddedEmphSpace bool // Track if we just added a space after emphasis
+	// Task list state: whether the content being rendered is a completed task item's,
+	// and the done/total task counts of each enclosing list when summaries are on
+	taskDone   bool
+	taskCounts [][2]int
+	// Footnote state: notes referenced but not yet printed, the notes already
+	// referenced, and the heading level that ends a section (0 unless footnotes or
+	// links are listed per section)
+	pendingNotes []*ast.Link
+	notesSeen    map[int]bool
+	sectionLevel int
+	// Link state: the destination the text being rendered links to while hyperlinks
+	// are on, and the destinations listed after the document in LinkFootnote mode
+	linkURL string
+	links   *linkRefs
 	// Table rendering state
 	inTable           bool
 	tableColumnWidths []int
-	tableCurrentRow   []string
-	tableRows         [][]string
+	tableCurrentRow   []tableCell
+	tableRows         []tableRow
 	tableAlignments   []ast.CellAlignFlags
-	isTableHeader     bool
-	inTableCell       bool
-	tableCellBuffer   *strings.Builder
+	tableSection      tableSection
+}
+
+// textStyle returns the style for regular text given the enclosing inline elements
+func (r *ANSIRenderer) textStyle() Style {
+	var style Style
+	if r.inLink {
+		style = style.merge(r.opts.Theme.Link)
+	}
+	if r.inStrong {
+		style = style.merge(r.opts.Theme.Strong)
+	}
+	if r.inEmph {
+		style = style.merge(r.opts.Theme.Emph)
+	}
+	if r.inDel {
+		style = style.merge(r.opts.Theme.Strikethrough)
+	}
+	if r.inMark {
+		style = style.merge(r.opts.Theme.Highlight)
+	}
+	if r.taskDone {
+		style = style.merge(r.opts.Theme.TaskDone)
+	}
+	return style
+}
+
+// spaceEmphasis separates an emphasis-like span (strong, emph, strikethrough, mark)
+// from the surrounding text, which the text wrapping trims.
+func (r *ANSIRenderer) spaceEmphasis(buf *bytes.Buffer, entering bool) {
+	if entering {
+		// Add space before the span if there's already content on the line
+		if r.currentLineLen > 0 {
+			buf.WriteString(" ")
+			r.currentLineLen++
+		}
+		r.justAddedEmphSpace = false
+	} else {
+		// Add space after the span
+		buf.WriteString(" ")
+		r.currentLineLen++
+		r.justAddedEmphSpace = true
+	}
+}
+
+// renderBlocks renders block nodes nested in a box or beside a label, such as the
+// content of an alert, into lines at most width columns wide. Each block is rendered
+// by a renderer of its own and separated from the next by a blank line.
+func (r *ANSIRenderer) renderBlocks(blocks []ast.Node, width int) []string {
+	var lines []string
+	for i, block := range blocks {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.renderBlock(r.subRenderer(width), block)...)
+	}
+	return lines
+}
+
+// subRenderer returns a renderer for content nested in r's output, such as a quote
+// or a list item, laid out in width columns.
+func (r *ANSIRenderer) subRenderer(width int) *ANSIRenderer {
+	opts := r.opts
+	// A zero width would select DefaultWidth, so even no room at all is one column
+	opts.Width, opts.Margin = max(width, 1), 0
+	sub := NewRenderer(opts)
+	sub.color, sub.profile = r.color, r.profile
+	sub.nested = true
+	sub.links = r.links
+	return sub
+}
+
+// renderBlock renders block with sub and returns its lines without the blank lines
+// around them.
+func (r *ANSIRenderer) renderBlock(sub *ANSIRenderer, block ast.Node) []string {
+	out := strings.Trim(sub.RenderNode(block), "\n")
+	// Footnotes referenced inside the block go in this renderer's notes section
+	for _, note := range sub.pendingNotes {
+		r.noteReferenced(note)
+	}
+	sub.pendingNotes = nil
+	return strings.Split(out, "\n")
 }
 
 // RenderNode recursively renders AST nodes
 func (r *ANSIRenderer) RenderNode(node ast.Node) string {
 	var buf bytes.Buffer
+	maxWidth := r.width()
+	wrapWidth := maxWidth
+	if r.opts.NoWrap {
+		wrapWidth = math.MaxInt32
+	}
 
 	ast.WalkFunc(node, func(node ast.Node, entering bool) ast.WalkStatus {
+		// Links listed per paragraph follow each top-level block, once it is written
+		if !entering && r.opts.LinkPlacement == LinksPerParagraph {
+			if _, ok := node.GetParent().(*ast.Document); ok {
+				defer r.writeBlockLinks(&buf, node)
+			}
+		}
+
 		switch n := node.(type) {
 		case *ast.Document:
-			// Root node, continue
+			if entering {
+				if r.opts.FootnotePlacement == FootnotesPerSection || r.opts.LinkPlacement == LinksPerSection {
+					r.sectionLevel = topHeadingLevel(n)
+				}
+			} else {
+				r.writeNotes(&buf)
+				r.writeLinks(&buf)
+			}
+
+		case *ast.Footnotes:
+			// Marks the start of the footnote definitions, which writeNotes prints
 
 		case *ast.Heading:
 			if entering {
+				if n.Level <= r.sectionLevel {
+					if r.opts.FootnotePlacement == FootnotesPerSection {
+						r.writeNotes(&buf)
+					}
+					if r.opts.LinkPlacement == LinksPerSection {
+						r.writeLinks(&buf)
+					}
+				}
 				buf.WriteString("\n")
 				r.inHeading = n.Level
 				r.currentLineLen = 0
-				// Show the # symbols in blue
-				prefixLen := 0
-				switch n.Level {
-				case 1:
-					buf.WriteString(color.BlueString("# "))
-					prefixLen = 2
-				case 2:
-					buf.WriteString(color.BlueString("## "))
-					prefixLen = 3
-				case 3:
-					buf.WriteString(color.BlueString("### "))
-					prefixLen = 4
-				case 4:
-					buf.WriteString(color.BlueString("#### "))
-					prefixLen = 5
-				case 5:
-					buf.WriteString(color.BlueString("##### "))
-					prefixLen = 6
-				case 6:
-					buf.WriteString(color.BlueString("###### "))
-					prefixLen = 7
-				}
-				r.currentLineLen = prefixLen
+				// Show the # symbols in the heading prefix style
+				prefix := r.opts.Theme.headingPrefix(n.Level)
+				buf.WriteString(r.paint(r.opts.Theme.HeadingPrefix, prefix))
+				r.currentLineLen = displayWidth(prefix)
 			} else {
 				buf.WriteString("\n")
 				r.inHeading = 0
@@ -351,12 +321,6 @@ func (r *ANSIRenderer) RenderNode(node ast.Node) string {
 			if entering {
 				text := string(n.Literal)
 
-				// If we're in a table cell, collect the text instead of rendering it
-				if r.inTableCell && r.tableCellBuffer != nil {
-					r.tableCellBuffer.WriteString(text)
-					return ast.GoToNext
-				}
-
 				// If we just added a space after emphasis and this text starts with a space, skip the leading space to avoid double spaces
 				if r.justAddedEmphSpace && len(text) > 0 && text[0] == ' ' {
 					text = text[1:]
@@ -366,256 +330,244 @@ func (r *ANSIRenderer) RenderNode(node ast.Node) string {
 					r.justAddedEmphSpace = false
 				}
 
-				wrappedText, newLineLen := wrapTextWithOffset(text, r.currentLineLen)
+				wrappedText, newLineLen := wrapTextWithOffset(text, r.currentLineLen, wrapWidth)
 
-				// Handle heading text - apply white bold color
+				// Handle heading text - apply the style for its level
 				if r.inHeading > 0 {
-					buf.WriteString(color.New(color.FgWhite, color.Bold).Sprint(wrappedText))
+					buf.WriteString(r.linked(r.paint(r.opts.Theme.headingStyle(r.inHeading), wrappedText)))
 					// Update line length (count only visible characters, not ANSI codes)
 					r.currentLineLen = newLineLen
 					return ast.GoToNext
 				}
 
 				// Apply formatting based on context for regular text
-				if r.inStrong && r.inEmph {
-					buf.WriteString(color.New(color.Bold, color.Italic, color.FgHiBlue).Sprint(wrappedText))
-				} else if r.inStrong {
-					buf.WriteString(color.New(color.Bold, color.FgHiBlue).Sprint(wrappedText))
-				} else if r.inEmph {
-					buf.WriteString(color.New(color.Italic, color.FgHiBlue).Sprint(wrappedText))
-				} else {
-					buf.WriteString(wrappedText)
-				}
+				buf.WriteString(r.linked(r.paint(r.textStyle(), wrappedText)))
 
 				// Update line length (count only visible characters, not ANSI codes)
 				// If wrappedText contains newlines, we're on a new line
 				if strings.Contains(wrappedText, "\n") {
 					lines := strings.Split(wrappedText, "\n")
 					lastLine := lines[len(lines)-1]
-					r.currentLineLen = len(lastLine)
+					r.currentLineLen = displayWidth(lastLine)
 				} else {
 					r.currentLineLen = newLineLen
 				}
 			}
 
 		case *ast.Emph:
-			if entering {
-				// If we're in a table cell, just mark emphasis but don't add spaces
-				if r.inTableCell {
-					r.inEmph = true
-				} else {
-					// Add space before emphasized text if there's already content on the line
-					if r.currentLineLen > 0 {
-						buf.WriteString(" ")
-						r.currentLineLen++
-					}
-					r.inEmph = true
-					r.justAddedEmphSpace = false
-				}
-			} else {
-				// If we're in a table cell, just unmark emphasis
-				if r.inTableCell {
-					r.inEmph = false
-				} else {
-					r.inEmph = false
-					// Add space after emphasized text
-					buf.WriteString(" ")
-					r.currentLineLen++
-					r.justAddedEmphSpace = true
-				}
-			}
+			r.inEmph = entering
+			r.spaceEmphasis(&buf, entering)
 
 		case *ast.Strong:
+			r.inStrong = entering
+			r.spaceEmphasis(&buf, entering)
+
+		case *ast.Del:
+			r.inDel = entering
+			r.spaceEmphasis(&buf, entering)
+
+		case *markNode:
+			r.inMark = entering
+			r.spaceEmphasis(&buf, entering)
+
+		case *ast.Superscript, *ast.Subscript:
 			if entering {
-				// If we're in a table cell, just mark strong but don't add spaces
-				if r.inTableCell {
-					r.inStrong = true
-				} else {
-					// Add space before strong text if there's already content on the line
-					if r.currentLineLen > 0 {
-						buf.WriteString(" ")
-						r.currentLineLen++
-					}
-					r.inStrong = true
-					r.justAddedEmphSpace = false
-				}
-			} else {
-				// If we're in a table cell, just unmark strong
-				if r.inTableCell {
-					r.inStrong = false
-				} else {
-					r.inStrong = false
-					// Add space after strong text
-					buf.WriteString(" ")
-					r.currentLineLen++
-					r.justAddedEmphSpace = true
-				}
+				_, super := n.(*ast.Superscript)
+				r.writeAttached(&buf, n, r.renderScript(string(n.AsLeaf().Literal), super, r.textStyle()))
 			}
 
 		case *ast.Link:
+			// Footnote references show the note number, the note itself goes in the notes section
+			if n.NoteID > 0 {
+				if entering {
+					r.writeAttached(&buf, n, r.paint(r.opts.Theme.Footnote, footnoteMarker(n.NoteID)))
+					r.noteReferenced(n)
+				}
+				return ast.SkipChildren
+			}
 			if entering {
-				buf.WriteString(color.BlueString(""))
-			} else {
+				r.spaceLink(&buf, n, entering)
+				r.inLink = true
+				r.enterLink(n.Destination)
+				return ast.GoToNext
+			}
+			r.inLink = false
+			r.linkURL = ""
+			switch r.linkMode() {
+			case LinkFootnote:
+				marker := r.paint(r.opts.Theme.LinkURL, r.linkReference(string(n.Destination)))
+				buf.WriteString(marker)
+				r.currentLineLen += displayWidth(marker)
+			case LinkInline:
 				url := string(n.Destination)
-				// Truncate long URLs to fit within maxLineWidth
-				urlDisplayLen := len(url)
-				if urlDisplayLen > maxLineWidth-10 {
-					url = url[:maxLineWidth-10] + "..."
-					urlDisplayLen = maxLineWidth - 7
-				}
+				// Truncate long URLs to fit within the line width
+				url = truncateWidth(url, maxWidth-7, "...")
 				linkText := fmt.Sprintf(" (%s)", url)
-				linkTextLen := len(linkText)
+				linkTextLen := displayWidth(linkText)
 
 				// Check if adding this link would exceed the line width
 				// Wrap if current line + link would exceed, or if we're already at/over the limit
 				if r.currentLineLen > 0 {
-					if r.currentLineLen+linkTextLen > maxLineWidth || r.currentLineLen >= maxLineWidth {
+					if r.currentLineLen+linkTextLen > maxWidth || r.currentLineLen >= maxWidth {
 						buf.WriteString("\n")
 						r.currentLineLen = 0
 					}
 				}
 
-				buf.WriteString(color.New(color.Faint).Sprintf(linkText))
+				buf.WriteString(r.paint(r.opts.Theme.LinkURL, linkText))
 				// Update line length (format: " (url)")
 				r.currentLineLen += linkTextLen
-				if r.currentLineLen > maxLineWidth {
+				if r.currentLineLen > maxWidth {
 					// Would exceed, but we already truncated
-					r.currentLineLen = maxLineWidth
+					r.currentLineLen = maxWidth
 				}
 			}
+			r.spaceLink(&buf, n, entering)
 
 		case *ast.Image:
 			if entering {
-				buf.WriteString(color.MagentaString("[Image: "))
+				r.spaceLink(&buf, n, entering)
+				r.enterLink(n.Destination)
+				buf.WriteString(r.linked(r.paint(r.opts.Theme.Image, "[Image: ")))
 				r.currentLineLen += 8 // "[Image: "
-			} else {
+				return ast.GoToNext
+			}
+			switch r.linkMode() {
+			case LinkInline:
 				url := string(n.Destination)
-				// Truncate long image URLs to fit within maxLineWidth
-				urlDisplayLen := len(url)
-				if urlDisplayLen > maxLineWidth-15 {
-					url = url[:maxLineWidth-15] + "..."
-					urlDisplayLen = maxLineWidth - 12
-				}
+				// Truncate long image URLs to fit within the line width
+				url = truncateWidth(url, maxWidth-12, "...")
 				imageText := fmt.Sprintf(" - %s", url)
-				buf.WriteString(color.New(color.Faint).Sprintf(imageText))
-				buf.WriteString(color.MagentaString("]"))
+				buf.WriteString(r.paint(r.opts.Theme.LinkURL, imageText))
+				buf.WriteString(r.paint(r.opts.Theme.Image, "]"))
 				// Update line length
-				r.currentLineLen += len(imageText) + 1 // +1 for "]"
-				if r.currentLineLen > maxLineWidth {
-					r.currentLineLen = maxLineWidth
+				r.currentLineLen += displayWidth(imageText) + 1 // +1 for "]"
+				if r.currentLineLen > maxWidth {
+					r.currentLineLen = maxWidth
 				}
+			case LinkFootnote:
+				marker := r.paint(r.opts.Theme.LinkURL, r.linkReference(string(n.Destination)))
+				buf.WriteString(r.paint(r.opts.Theme.Image, "]") + marker)
+				r.currentLineLen += 1 + displayWidth(marker)
+			default:
+				buf.WriteString(r.linked(r.paint(r.opts.Theme.Image, "]")))
+				r.currentLineLen++
 			}
+			r.linkURL = ""
+			r.spaceLink(&buf, n, entering)
 
 		case *ast.Code:
 			if entering {
 				code := string(n.Literal)
 
-				// If we're in a table cell, collect the code instead of rendering it
-				if r.inTableCell && r.tableCellBuffer != nil {
-					r.tableCellBuffer.WriteString(code)
-					return ast.GoToNext
-				}
-
-				// Truncate very long inline code to fit within maxLineWidth
-				codeDisplayLen := len(code)
-				if codeDisplayLen > maxLineWidth-2 {
-					code = code[:maxLineWidth-5] + "..."
-					codeDisplayLen = maxLineWidth - 2
-				}
+				// Truncate very long inline code to fit within the line width
+				code = truncateWidth(code, maxWidth-2, "...")
 				codeText := " " + code + " "
-				codeTextLen := len(codeText)
+				codeTextLen := displayWidth(codeText)
 
 				// Check if adding this code would exceed the line width
 				// Wrap if current line + code would exceed, or if we're already at/over the limit
 				if r.currentLineLen > 0 {
-					if r.currentLineLen+codeTextLen > maxLineWidth || r.currentLineLen >= maxLineWidth {
+					if r.currentLineLen+codeTextLen > maxWidth || r.currentLineLen >= maxWidth {
 						buf.WriteString("\n")
 						r.currentLineLen = 0
 					}
 				}
 
-				buf.WriteString(color.New(color.FgHiRed).Sprint(codeText))
+				buf.WriteString(r.linked(r.paint(r.opts.Theme.Code, codeText)))
 				// Update line length
 				r.currentLineLen += codeTextLen
-				if r.currentLineLen > maxLineWidth {
-					r.currentLineLen = maxLineWidth
+				if r.currentLineLen > maxWidth {
+					r.currentLineLen = maxWidth
 				}
 			}
 
 		case *ast.CodeBlock:
 			if entering {
 				r.inCodeBlock = true
-				boxWidth := maxLineWidth
-				buf.WriteString("\n")
-				buf.WriteString(color.New(color.FgHiBlack).Sprint("┌" + strings.Repeat("─", boxWidth) + "┐\n"))
-				lines := strings.Split(string(n.Literal), "\n")
-				for i, line := range lines {
-					// Skip the last line if it's empty (trailing newline)
-					if i == len(lines)-1 && line == "" {
-						continue
-					}
-					// Wrap long lines within code blocks
-					if len(line) > boxWidth-2 {
-						// Split long lines
-						for len(line) > boxWidth-2 {
-							chunk := line[:boxWidth-2]
-							line = line[boxWidth-2:]
-							buf.WriteString(color.New(color.FgHiBlack).Sprint("│ "))
-							buf.WriteString(color.New(color.FgHiMagenta).Sprint(chunk))
-							buf.WriteString(color.New(color.FgHiBlack).Sprint(" │\n"))
-						}
-					}
-					// Pad the line to ensure the right border aligns
-					paddedLine := line
-					if len(line) < boxWidth-2 {
-						paddedLine = line + strings.Repeat(" ", boxWidth-2-len(line))
-					}
-					buf.WriteString(color.New(color.FgHiBlack).Sprint("│ "))
-					buf.WriteString(color.New(color.FgHiMagenta).Sprint(paddedLine))
-					buf.WriteString(color.New(color.FgHiBlack).Sprint(" │\n"))
-				}
-				buf.WriteString(color.New(color.FgHiBlack).Sprint("└" + strings.Repeat("─", boxWidth) + "┘\n"))
+				buf.WriteString(r.renderCodeBlock(n, maxWidth))
 				r.currentLineLen = 0
 			} else {
 				r.inCodeBlock = false
 			}
 
 		case *ast.List:
+			if n.IsFootnotesList {
+				return ast.SkipChildren
+			}
 			if entering {
 				r.listLevel++
 				r.listIndex[r.listLevel] = 0
+				if n.ListFlags&ast.ListTypeOrdered != 0 {
+					r.listMarkerWidth[r.listLevel] = r.orderedMarkerWidth(n, r.listLevel)
+				}
+				if r.opts.TaskSummary {
+					done, total := countTasks(n)
+					r.taskCounts = append(r.taskCounts, [2]int{done, total})
+				}
 			} else {
+				if r.opts.TaskSummary {
+					counts := r.taskCounts[len(r.taskCounts)-1]
+					r.taskCounts = r.taskCounts[:len(r.taskCounts)-1]
+					if counts[1] > 0 {
+						buf.WriteString(r.paint(r.opts.Theme.Task, fmt.Sprintf("%d/%d done", counts[0], counts[1])) + "\n")
+					}
+				}
 				r.listLevel--
 				buf.WriteString("\n")
 				r.currentLineLen = 0
 			}
 
 		case *ast.ListItem:
+			if n.ListFlags&ast.ListTypeDefinition != 0 {
+				if entering {
+					buf.WriteString(r.renderDefinitionItem(n, maxWidth))
+				} else if n.ListFlags&ast.ListTypeTerm == 0 {
+					// Terms sit directly above their definitions, which are set apart
+					buf.WriteString("\n")
+				}
+				r.currentLineLen = 0
+				return ast.SkipChildren
+			}
 			if entering {
 				r.listIndex[r.listLevel]++
-				indent := strings.Repeat("  ", r.listLevel-1)
-				indentLen := len(indent)
+				isTask, done := taskState(n)
 
-				// Check if parent is ordered list
+				// Check if parent is ordered list; numbers are right-aligned so the text lines up
+				var marker, prefix string
+				guide, continuation := r.listGuides(n)
 				parent := n.GetParent()
 				if list, ok := parent.(*ast.List); ok && list.ListFlags&ast.ListTypeOrdered != 0 {
-					prefix := fmt.Sprintf("%d. ", r.listIndex[r.listLevel])
-					buf.WriteString(indent + color.YellowString(prefix))
-					r.currentLineLen = indentLen + len(prefix)
-				} else {
-					prefix := "• "
-					buf.WriteString(indent + color.YellowString(prefix))
-					r.currentLineLen = indentLen + len(prefix)
+					number := listStart(list) + r.listIndex[r.listLevel] - 1
+					marker = padLeft(r.orderedMarker(list, r.listLevel, number), r.listMarkerWidth[r.listLevel]) + " "
+					prefix = r.paint(r.opts.Theme.Bullet, marker)
+				} else if !isTask {
+					marker = r.opts.Theme.bullet(r.listLevel) + " "
+					prefix = r.paint(r.opts.Theme.Bullet, marker)
 				}
-			} else {
-				buf.WriteString("\n")
+
+				// Task items show a checkbox in place of the bullet, or after the number
+				if isTask {
+					stripTaskMarker(n)
+					glyph := r.opts.Theme.taskGlyph(done) + " "
+					marker += glyph
+					prefix += r.paint(r.opts.Theme.Task, glyph)
+				}
+
+				item := itemMarker{prefix: prefix, width: displayWidth(marker), guide: guide, continuation: continuation}
+				buf.WriteString(r.renderListItem(n, item, isTask && done, maxWidth))
 				r.currentLineLen = 0
+			} else if list, ok := n.GetParent().(*ast.List); ok && !list.Tight && ast.GetNextNode(n) != nil {
+				// Items of a loose list are separated by blank lines, as in the source
+				_, continuation := r.listGuides(n)
+				buf.WriteString(r.paint(r.opts.Theme.ListGuide, strings.TrimRight(continuation, " ")) + "\n")
 			}
+			return ast.SkipChildren
 
 		case *ast.Table:
 			if entering {
 				r.inTable = true
-				r.tableRows = make([][]string, 0)
+				r.tableRows = make([]tableRow, 0)
 				r.tableCurrentRow = nil
 				r.tableColumnWidths = nil
 				r.tableAlignments = nil
@@ -633,53 +585,80 @@ func (r *ANSIRenderer) RenderNode(node ast.Node) string {
 
 		case *ast.TableHeader:
 			if entering {
-				r.isTableHeader = true
+				r.tableSection = sectionHeader
 			} else {
-				r.isTableHeader = false
+				r.tableSection = sectionBody
 			}
 
 		case *ast.TableBody:
 			// TableBody is just a container, no special handling needed
 			// entering/leaving doesn't need special logic
 
+		case *ast.TableFooter:
+			if entering {
+				r.tableSection = sectionFooter
+			} else {
+				r.tableSection = sectionBody
+			}
+
 		case *ast.TableRow:
 			if entering {
-				r.tableCurrentRow = make([]string, 0)
+				r.tableCurrentRow = make([]tableCell, 0)
 			} else {
 				// Row complete, add it to tableRows
 				if len(r.tableCurrentRow) > 0 {
-					r.tableRows = append(r.tableRows, r.tableCurrentRow)
+					r.tableRows = append(r.tableRows, tableRow{cells: r.tableCurrentRow, section: r.tableSection})
 				}
 				r.tableCurrentRow = nil
 			}
 
 		case *ast.TableCell:
 			if entering {
-				// Start collecting cell content
-				r.inTableCell = true
-				r.tableCellBuffer = &strings.Builder{}
-				// Store alignment for first row (header)
-				if r.isTableHeader && len(r.tableAlignments) < len(r.tableCurrentRow)+1 {
-					r.tableAlignments = append(r.tableAlignments, n.Align)
+				// Render the cell's inline content up front so widths can be measured on it
+				style := Style{}
+				switch r.tableSection {
+				case sectionHeader:
+					style = r.opts.Theme.TableHeader
+				case sectionFooter:
+					style = r.opts.Theme.TableFooter
 				}
-			} else {
-				// Cell complete, add content to current row
-				cellContent := strings.TrimSpace(r.tableCellBuffer.String())
-				r.tableCurrentRow = append(r.tableCurrentRow, cellContent)
-				r.inTableCell = false
-				r.tableCellBuffer = nil
+				r.tableCurrentRow = append(r.tableCurrentRow, tableCell{
+					content: r.renderInline(n, style),
+					span:    max(n.ColSpan, 1),
+					align:   n.Align,
+				})
+				return ast.SkipChildren
 			}
 
 		case *ast.BlockQuote:
+			if kind, marker, ok := alertMarker(n); ok && entering {
+				stripAlertMarker(marker)
+				buf.WriteString(r.renderAlert(n, kind, maxWidth))
+				r.currentLineLen = 0
+				return ast.SkipChildren
+			}
+			if entering {
+				buf.WriteString(r.renderQuote(n, maxWidth))
+				r.currentLineLen = 0
+			}
+			return ast.SkipChildren
+
+		case *ast.Aside:
+			if entering {
+				buf.WriteString(r.renderAlert(n, alertAside, maxWidth))
+				r.currentLineLen = 0
+			}
+			return ast.SkipChildren
+
+		case *ast.Callout:
 			if entering {
-				buf.WriteString(color.New(color.FgHiBlack).Sprint("│ "))
-				r.currentLineLen += 2 // "│ "
+				r.writeAttached(&buf, n, r.paint(r.opts.Theme.Callout, calloutMarker(n.ID)))
 			}
 
 		case *ast.HorizontalRule:
 			if entering {
 				buf.WriteString("\n")
-				buf.WriteString(color.New(color.FgHiBlack).Sprint(strings.Repeat("─", maxLineWidth)))
+				buf.WriteString(r.paint(r.opts.Theme.HorizontalRule, strings.Repeat("─", maxWidth)))
 				buf.WriteString("\n\n")
 				r.currentLineLen = 0
 			}
@@ -694,5 +673,5 @@ func (r *ANSIRenderer) RenderNode(node ast.Node) string {
 		return ast.GoToNext
 	})
 
-	return buf.String()
+	return indentLines(buf.String(), r.opts.Margin)
 }
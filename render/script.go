@@ -0,0 +1,87 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// superscripts and subscripts map the characters that have a Unicode
+// superscript or subscript form. Letters are incomplete in both sets
+// (there is no superscript q, and few subscript letters).
+var (
+	superscripts = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+		'+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+		'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'f': 'ᶠ', 'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ',
+		'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ', 'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ',
+		't': 'ᵗ', 'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ', 'z': 'ᶻ',
+	}
+	subscripts = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+		'+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎',
+		'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ',
+		'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ', 's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ',
+	}
+)
+
+// scriptText converts text to its Unicode superscript or subscript form.
+// It reports false if any character has no such form, so that the caller can
+// fall back to the caret form rather than mixing raised and plain characters.
+func scriptText(text string, super bool) (string, bool) {
+	table := subscripts
+	if super {
+		table = superscripts
+	}
+	var b strings.Builder
+	for _, c := range text {
+		mapped, ok := table[c]
+		if !ok {
+			return "", false
+		}
+		b.WriteRune(mapped)
+	}
+	return b.String(), true
+}
+
+// renderScript renders the text of a superscript or subscript in style, using
+// Unicode characters where possible and a dimmed ^text or _text otherwise.
+func (r *ANSIRenderer) renderScript(text string, super bool, style Style) string {
+	if converted, ok := scriptText(text, super); ok {
+		return r.paint(style, converted)
+	}
+	marker := "_"
+	if super {
+		marker = "^"
+	}
+	return r.paint(style.merge(Style{Faint: true}), marker+text)
+}
+
+// writeAttached writes text that is attached to the word before it, such as a
+// superscript or a footnote marker, for node. Text wrapping trims the spaces around
+// node, so the ones the source had are put back.
+func (r *ANSIRenderer) writeAttached(buf *bytes.Buffer, node ast.Node, text string) {
+	before, after := spacedScript(node)
+	if before && r.currentLineLen > 0 {
+		text = " " + text
+	}
+	if after {
+		text += " "
+		r.justAddedEmphSpace = true
+	}
+	buf.WriteString(text)
+	r.currentLineLen += displayWidth(text)
+}
+
+// spacedScript reports whether the text next to a superscript or subscript
+// node is separated from it by whitespace, before and after.
+func spacedScript(node ast.Node) (before, after bool) {
+	if prev, ok := ast.GetPrevNode(node).(*ast.Text); ok {
+		before = strings.TrimRight(string(prev.Literal), " \t\n") != string(prev.Literal)
+	}
+	if next, ok := ast.GetNextNode(node).(*ast.Text); ok {
+		after = strings.TrimLeft(string(next.Literal), " \t\n") != string(next.Literal)
+	}
+	return before, after
+}
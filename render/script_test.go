@@ -0,0 +1,78 @@
+package render
+
+import "testing"
+
+func TestScriptText(t *testing.T) {
+	tests := []struct {
+		text   string
+		super  bool
+		want   string
+		wantOK bool
+	}{
+		{text: "10", super: true, want: "¹⁰", wantOK: true},
+		{text: "-n", super: true, want: "⁻ⁿ", wantOK: true},
+		{text: "2", super: false, want: "₂", wantOK: true},
+		{text: "i+1", super: false, want: "ᵢ₊₁", wantOK: true},
+		{text: "q", super: true, wantOK: false},
+		{text: "b", super: false, wantOK: false},
+		{text: "A", super: true, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := scriptText(tt.text, tt.super)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("scriptText(%q, %v) = %q, %v, want %q, %v", tt.text, tt.super, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRender_SuperAndSubscript(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "Superscript digits",
+			markdown: "E = mc^2^ holds",
+			want:     "E = mc² holds",
+		},
+		{
+			name:     "Subscript digits",
+			markdown: "Water is H~2~O.",
+			want:     "Water is H₂O.",
+		},
+		{
+			name:     "Caret form for unmapped characters",
+			markdown: "see note^Q^ below",
+			want:     "see note^Q below",
+		},
+		{
+			name:     "Spaced superscript",
+			markdown: "the ^th^ suffix",
+			want:     "the ᵗʰ suffix",
+		},
+		{
+			name:     "Strikethrough is not a subscript",
+			markdown: "~~gone~~",
+			want:     "gone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{ColorMode: ColorNever})
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got: %q", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestRender_CaretFormIsDimmed(t *testing.T) {
+	result := RenderToStringWithOptions("x^Q^", Options{Theme: MonochromeTheme(), ColorMode: ColorAlways, ColorProfile: ProfileANSI})
+	// 2 is the SGR parameter for faint text
+	if !contains(result, "\x1b[2m^Q") {
+		t.Errorf("Expected the caret form to be dimmed, got %q", result)
+	}
+}
@@ -0,0 +1,301 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidStyle is returned when a style file cannot be turned into a Theme.
+var ErrInvalidStyle = errors.New("invalid style")
+
+// maxBulletWidth bounds bullet glyphs so a typo cannot push list text off screen.
+const maxBulletWidth = 4
+
+// styleFile is the on-disk representation of a custom theme.
+//
+// Example:
+//
+//	{
+//	  "name": "house",
+//	  "base": "dark",
+//	  "elements": {
+//	    "heading1": {"fg": "cyan", "bold": true, "underline": true},
+//	    "code": {"fg": "#5fd75f", "bg": "black"}
+//	  },
+//	  "decorations": {
+//	    "bullets": ["-", "*"],
+//	    "heading_prefixes": ["▌ ", "▌▌ "]
+//	  }
+//	}
+type styleFile struct {
+	Name        string                     `json:"name"`
+	Base        string                     `json:"base"`
+	Elements    map[string]json.RawMessage `json:"elements"`
+	Decorations *styleDecorations          `json:"decorations"`
+}
+
+// styleSpec describes a single element in a style file.
+type styleSpec struct {
+	Foreground    string `json:"fg"`
+	Background    string `json:"bg"`
+	Bold          bool   `json:"bold"`
+	Italic        bool   `json:"italic"`
+	Underline     bool   `json:"underline"`
+	Faint         bool   `json:"faint"`
+	Strikethrough bool   `json:"strikethrough"`
+}
+
+// styleDecorations holds the non-color parts of a style file.
+type styleDecorations struct {
+	Bullets         []string `json:"bullets"`
+	HeadingPrefixes []string `json:"heading_prefixes"`
+	TaskGlyphs      []string `json:"task_glyphs"`
+	Numbering       []string `json:"numbering"`
+}
+
+// LoadStyleFile reads a JSON style file and returns the Theme it describes.
+func LoadStyleFile(path string) (*Theme, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return nil, fmt.Errorf("failed to load style %q: %w: YAML style files are not supported, convert it to JSON", path, ErrInvalidStyle)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read style %q: %w", path, err)
+	}
+
+	theme, err := ParseStyle(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load style %q: %w", path, err)
+	}
+	return theme, nil
+}
+
+// ParseStyle decodes a JSON style document into a Theme.
+// Elements not mentioned in the document keep the styles of its base theme,
+// which defaults to the dark theme.
+func ParseStyle(data []byte) (*Theme, error) {
+	var file styleFile
+	if err := decodeStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStyle, describeJSONError(data, err))
+	}
+
+	base := file.Base
+	if base == "" {
+		base = "dark"
+	}
+	theme, err := ThemeByName(base)
+	if err != nil {
+		return nil, fmt.Errorf("%w: base: %w", ErrInvalidStyle, err)
+	}
+	theme.Name = "custom"
+	if file.Name != "" {
+		theme.Name = file.Name
+	}
+
+	// Apply elements in sorted order so the first reported error is deterministic
+	names := make([]string, 0, len(file.Elements))
+	for name := range file.Elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := themeElement(theme, name)
+		if target == nil {
+			return nil, fmt.Errorf("%w: elements.%s: unknown element (known elements: %s)",
+				ErrInvalidStyle, name, strings.Join(themeElementNames(), ", "))
+		}
+		style, err := parseStyleSpec(file.Elements[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: elements.%s: %w", ErrInvalidStyle, name, err)
+		}
+		*target = style
+	}
+
+	if file.Decorations != nil {
+		if err := applyDecorations(theme, file.Decorations); err != nil {
+			return nil, fmt.Errorf("%w: decorations.%w", ErrInvalidStyle, err)
+		}
+	}
+
+	return theme, nil
+}
+
+// parseStyleSpec decodes and validates a single element style.
+func parseStyleSpec(raw json.RawMessage) (Style, error) {
+	var spec styleSpec
+	if err := decodeStrict(raw, &spec); err != nil {
+		return Style{}, errors.New(describeJSONError(raw, err))
+	}
+
+	fg, err := parseColor(spec.Foreground)
+	if err != nil {
+		return Style{}, fmt.Errorf("fg: %w", err)
+	}
+	bg, err := parseColor(spec.Background)
+	if err != nil {
+		return Style{}, fmt.Errorf("bg: %w", err)
+	}
+
+	return Style{
+		Foreground:    fg,
+		Background:    bg,
+		Bold:          spec.Bold,
+		Italic:        spec.Italic,
+		Underline:     spec.Underline,
+		Faint:         spec.Faint,
+		Strikethrough: spec.Strikethrough,
+	}, nil
+}
+
+// parseColor validates a color name or hex value from a style file.
+func parseColor(value string) (Color, error) {
+	c := Color(strings.ToLower(strings.TrimSpace(value)))
+	if !c.valid() {
+		if strings.HasPrefix(string(c), "#") {
+			return "", fmt.Errorf("invalid hex color %q (expected #rgb or #rrggbb)", value)
+		}
+		return "", fmt.Errorf("unknown color %q (use #rrggbb or one of: %s)", value, strings.Join(colorNames(), ", "))
+	}
+	return c, nil
+}
+
+// applyDecorations copies validated decorations into theme.
+// Errors name the offending field relative to the decorations object.
+func applyDecorations(theme *Theme, d *styleDecorations) error {
+	if d.Bullets != nil {
+		if len(d.Bullets) == 0 {
+			return errors.New("bullets: must contain at least one glyph")
+		}
+		for i, bullet := range d.Bullets {
+			if strings.TrimSpace(bullet) == "" {
+				return fmt.Errorf("bullets[%d]: must not be blank", i)
+			}
+			if n := displayWidth(bullet); n > maxBulletWidth {
+				return fmt.Errorf("bullets[%d]: %q is %d columns wide, at most %d allowed", i, bullet, n, maxBulletWidth)
+			}
+		}
+		theme.Bullets = append([]string(nil), d.Bullets...)
+	}
+
+	if d.Numbering != nil {
+		if len(d.Numbering) == 0 {
+			return errors.New("numbering: must contain at least one style")
+		}
+		numbering := make([]NumberStyle, len(d.Numbering))
+		for i, name := range d.Numbering {
+			style, err := ParseNumberStyle(name)
+			if err != nil {
+				return fmt.Errorf("numbering[%d]: %w", i, err)
+			}
+			numbering[i] = style
+		}
+		theme.Numbering = numbering
+	}
+
+	if d.TaskGlyphs != nil {
+		if len(d.TaskGlyphs) != len(theme.TaskGlyphs) {
+			return fmt.Errorf("task_glyphs: has %d entries, expected %d (open and done)", len(d.TaskGlyphs), len(theme.TaskGlyphs))
+		}
+		for i, glyph := range d.TaskGlyphs {
+			if strings.TrimSpace(glyph) == "" {
+				return fmt.Errorf("task_glyphs[%d]: must not be blank", i)
+			}
+			if n := displayWidth(glyph); n > maxBulletWidth {
+				return fmt.Errorf("task_glyphs[%d]: %q is %d columns wide, at most %d allowed", i, glyph, n, maxBulletWidth)
+			}
+			theme.TaskGlyphs[i] = glyph
+		}
+	}
+
+	if d.HeadingPrefixes != nil {
+		if len(d.HeadingPrefixes) > len(theme.HeadingPrefixes) {
+			return fmt.Errorf("heading_prefixes: has %d entries, at most %d allowed", len(d.HeadingPrefixes), len(theme.HeadingPrefixes))
+		}
+		for i, prefix := range d.HeadingPrefixes {
+			theme.HeadingPrefixes[i] = prefix
+		}
+	}
+
+	return nil
+}
+
+// decodeStrict decodes JSON into v, rejecting unknown fields and trailing data.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the top-level JSON value")
+	}
+	return nil
+}
+
+// describeJSONError turns decoder errors into messages that point at the problem.
+func describeJSONError(data []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the bytes read, so the offending byte is the last one
+		line, col := lineAndColumn(data, syntaxErr.Offset-1)
+		return fmt.Sprintf("line %d, column %d: %v", line, col, syntaxErr)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("%s: expected %s, got JSON %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	// Unknown fields are reported by encoding/json as `json: unknown field "x"`
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+// lineAndColumn converts a byte offset into 1-based line and column numbers.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// themeElement returns a pointer to the style of the named element, or nil.
+func themeElement(t *Theme, name string) *Style {
+	for _, el := range themeElements {
+		if el.name == name {
+			return el.style(t)
+		}
+	}
+	return nil
+}
+
+// themeElementNames lists the element names accepted by style files.
+func themeElementNames() []string {
+	names := make([]string, len(themeElements))
+	for i, el := range themeElements {
+		names[i] = el.name
+	}
+	return names
+}
+
+// colorNames lists the accepted color names in sorted order.
+func colorNames() []string {
+	names := make([]string, 0, len(namedColors))
+	for name := range namedColors {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
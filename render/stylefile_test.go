@@ -0,0 +1,168 @@
+package render
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseStyle(t *testing.T) {
+	data := []byte(`{
+		"name": "house",
+		"base": "light",
+		"elements": {
+			"heading1": {"fg": "cyan", "bold": true, "underline": true},
+			"code": {"fg": "Green", "bg": "black"}
+		},
+		"decorations": {
+			"bullets": ["-", "*"],
+			"heading_prefixes": ["> "]
+		}
+	}`)
+
+	theme, err := ParseStyle(data)
+	if err != nil {
+		t.Fatalf("ParseStyle() error = %v", err)
+	}
+
+	if theme.Name != "house" {
+		t.Errorf("Name = %q, want %q", theme.Name, "house")
+	}
+	if want := (Style{Foreground: "cyan", Bold: true, Underline: true}); theme.Headings[0] != want {
+		t.Errorf("Headings[0] = %+v, want %+v", theme.Headings[0], want)
+	}
+	if want := (Style{Foreground: "green", Background: "black"}); theme.Code != want {
+		t.Errorf("Code = %+v, want %+v", theme.Code, want)
+	}
+	if want := LightTheme().Strong; theme.Strong != want {
+		t.Errorf("Strong should be inherited from the base theme, got %+v, want %+v", theme.Strong, want)
+	}
+	if got := theme.bullet(2); got != "*" {
+		t.Errorf("bullet(2) = %q, want %q", got, "*")
+	}
+	if got := theme.headingPrefix(1); got != "> " {
+		t.Errorf("headingPrefix(1) = %q, want %q", got, "> ")
+	}
+	if got := theme.headingPrefix(2); got != "## " {
+		t.Errorf("headingPrefix(2) = %q, want %q", got, "## ")
+	}
+}
+
+func TestParseStyle_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantMsg string
+	}{
+		{
+			name:    "Syntax error",
+			data:    "{\n  \"elements\": {,}\n}",
+			wantMsg: "line 2, column 16",
+		},
+		{
+			name:    "Unknown top-level field",
+			data:    `{"colours": {}}`,
+			wantMsg: `unknown field "colours"`,
+		},
+		{
+			name:    "Unknown base theme",
+			data:    `{"base": "solarized"}`,
+			wantMsg: `base: unknown theme "solarized"`,
+		},
+		{
+			name:    "Unknown element",
+			data:    `{"elements": {"headline": {"bold": true}}}`,
+			wantMsg: "elements.headline: unknown element",
+		},
+		{
+			name:    "Unknown color",
+			data:    `{"elements": {"strong": {"fg": "bleu"}}}`,
+			wantMsg: `elements.strong: fg: unknown color "bleu"`,
+		},
+		{
+			name:    "Wrong attribute type",
+			data:    `{"elements": {"emph": {"italic": "yes"}}}`,
+			wantMsg: "elements.emph: italic: expected bool, got JSON string",
+		},
+		{
+			name:    "Unknown style attribute",
+			data:    `{"elements": {"emph": {"blink": true}}}`,
+			wantMsg: `elements.emph: unknown field "blink"`,
+		},
+		{
+			name:    "Blank bullet",
+			data:    `{"decorations": {"bullets": ["-", " "]}}`,
+			wantMsg: "decorations.bullets[1]: must not be blank",
+		},
+		{
+			name:    "Too many heading prefixes",
+			data:    `{"decorations": {"heading_prefixes": ["1","2","3","4","5","6","7"]}}`,
+			wantMsg: "decorations.heading_prefixes: has 7 entries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStyle([]byte(tt.data))
+			if !errors.Is(err, ErrInvalidStyle) {
+				t.Fatalf("ParseStyle() error = %v, want ErrInvalidStyle", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ParseStyle() error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestLoadStyleFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("JSON file", func(t *testing.T) {
+		path := filepath.Join(dir, "house.json")
+		if err := os.WriteFile(path, []byte(`{"elements": {"bullet": {"fg": "green"}}}`), 0o600); err != nil {
+			t.Fatalf("failed to write style file: %v", err)
+		}
+
+		theme, err := LoadStyleFile(path)
+		if err != nil {
+			t.Fatalf("LoadStyleFile() error = %v", err)
+		}
+		result := RenderToStringWithOptions("- item", Options{Theme: theme, ColorMode: ColorAlways})
+		if !contains(result, "\x1b[32m• ") {
+			t.Errorf("bullet should use the loaded style, got: %q", result)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadStyleFile(filepath.Join(dir, "missing.json"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("LoadStyleFile() error = %v, want os.ErrNotExist", err)
+		}
+	})
+
+	t.Run("YAML file", func(t *testing.T) {
+		_, err := LoadStyleFile(filepath.Join(dir, "house.yaml"))
+		if !errors.Is(err, ErrInvalidStyle) {
+			t.Errorf("LoadStyleFile() error = %v, want ErrInvalidStyle", err)
+		}
+	})
+}
+
+func TestParseStyle_Numbering(t *testing.T) {
+	theme, err := ParseStyle([]byte(`{"decorations": {"numbering": ["1", "a", "I"]}}`))
+	if err != nil {
+		t.Fatalf("ParseStyle() error = %v", err)
+	}
+	for depth, want := range map[int]NumberStyle{1: NumberDecimal, 2: NumberLowerAlpha, 3: NumberUpperRoman, 5: NumberUpperRoman} {
+		if got := theme.numbering(depth); got != want {
+			t.Errorf("numbering(%d) = %v, want %v", depth, got, want)
+		}
+	}
+
+	_, err = ParseStyle([]byte(`{"decorations": {"numbering": ["1", "x"]}}`))
+	if !errors.Is(err, ErrInvalidStyle) || !strings.Contains(err.Error(), "decorations.numbering[1]: unknown numbering style") {
+		t.Errorf("ParseStyle() error = %v, want an unknown numbering style error", err)
+	}
+}
@@ -0,0 +1,616 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+// TableLayout selects how tables are drawn.
+type TableLayout int
+
+const (
+	// TableLayoutAuto draws a grid, switching to records when columns would become too narrow to read.
+	TableLayoutAuto TableLayout = iota
+	// TableLayoutGrid always draws a grid of rows and columns.
+	TableLayoutGrid
+	// TableLayoutRecords draws each row as a block of "header │ value" lines.
+	TableLayoutRecords
+)
+
+// String returns the flag spelling of l.
+func (l TableLayout) String() string {
+	switch l {
+	case TableLayoutGrid:
+		return "grid"
+	case TableLayoutRecords:
+		return "records"
+	default:
+		return "auto"
+	}
+}
+
+// ParseTableLayout parses "auto", "grid" or "records".
+func ParseTableLayout(s string) (TableLayout, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "auto", "":
+		return TableLayoutAuto, nil
+	case "grid":
+		return TableLayoutGrid, nil
+	case "records":
+		return TableLayoutRecords, nil
+	default:
+		return TableLayoutAuto, fmt.Errorf("unknown table layout %q (expected auto, grid or records)", s)
+	}
+}
+
+const (
+	// minTableColumnWidth is the narrowest a table column is laid out, leaving room for "...".
+	minTableColumnWidth = 3
+	// minReadableColumnWidth is the width below which the auto layout gives up on a grid
+	// for columns whose content is wider.
+	minReadableColumnWidth = 8
+)
+
+// tableSection is the part of a table a row belongs to.
+type tableSection int
+
+const (
+	sectionBody tableSection = iota
+	sectionHeader
+	sectionFooter
+)
+
+// tableCell is a table cell rendered to a styled string.
+type tableCell struct {
+	content string
+	// span is the number of columns the cell covers, at least 1.
+	span  int
+	align ast.CellAlignFlags
+}
+
+// tableRow is a row of cells and the section it belongs to.
+type tableRow struct {
+	cells   []tableCell
+	section tableSection
+}
+
+// placedCell is a cell positioned on the column grid.
+type placedCell struct {
+	tableCell
+	col int
+}
+
+// tableColumnCount returns the number of columns the table needs. Rows may be ragged:
+// shorter rows leave their last columns empty and longer rows add columns. Empty
+// cells past the header are not counted, since the parser pads every row to the
+// header's cell count even when spanning cells already cover those columns.
+func (r *ANSIRenderer) tableColumnCount() int {
+	numCols := 0
+	for _, row := range r.tableRows {
+		col, used := 0, 0
+		for _, cell := range row.cells {
+			col += cell.span
+			if row.section == sectionHeader || cell.content != "" {
+				used = col
+			}
+		}
+		numCols = max(numCols, used)
+	}
+	return max(numCols, 1)
+}
+
+// placeCells positions the cells of row on the column grid, dropping cells that start
+// past the last column and clamping spans to the columns that remain.
+func placeCells(row tableRow, numCols int) []placedCell {
+	var placed []placedCell
+	col := 0
+	for _, cell := range row.cells {
+		if col >= numCols {
+			break
+		}
+		cell.span = min(cell.span, numCols-col)
+		placed = append(placed, placedCell{tableCell: cell, col: col})
+		col += cell.span
+	}
+	return placed
+}
+
+// columnAlignments returns the alignment of each column, taken from the first cell
+// that starts in it, header cells first.
+func (r *ANSIRenderer) columnAlignments(numCols int) []ast.CellAlignFlags {
+	alignments := make([]ast.CellAlignFlags, numCols)
+	seen := make([]bool, numCols)
+	for _, row := range r.tableRows {
+		for _, cell := range placeCells(row, numCols) {
+			if !seen[cell.col] {
+				alignments[cell.col] = cell.align
+				seen[cell.col] = true
+			}
+		}
+	}
+	return alignments
+}
+
+// spanWidth returns the width available to a cell covering span columns from col,
+// including the borders and padding it absorbs between them.
+func (r *ANSIRenderer) spanWidth(col, span int, borders borderSet) int {
+	return sum(r.tableColumnWidths[col:col+span]) + (span-1)*(displayWidth(borders.middle)+2)
+}
+
+// calculateTableColumnWidths distributes the available line width between the columns.
+// Each column wants the width of its widest cell and needs at least the width of its
+// longest word. Columns get what they want when the table fits; otherwise every column
+// gets what it needs and the remaining space is shared in proportion to how much more
+// each one wants, so short columns stay intact and long text wraps. In truncate mode
+// long columns share the width in proportion to their content instead.
+// It reports whether every column stays readable, that is no narrower than
+// minReadableColumnWidth unless its content is narrower still.
+func (r *ANSIRenderer) calculateTableColumnWidths() bool {
+	if len(r.tableRows) == 0 {
+		return true
+	}
+
+	numCols := r.tableColumnCount()
+	borders := r.tableBorders()
+	want := make([]int, numCols)
+	need := make([]int, numCols)
+	var spanning []placedCell
+	for _, row := range r.tableRows {
+		for _, cell := range placeCells(row, numCols) {
+			if cell.span > 1 {
+				spanning = append(spanning, cell)
+				continue
+			}
+			want[cell.col] = max(want[cell.col], displayWidth(cell.content))
+			need[cell.col] = max(need[cell.col], longestWord(cell.content))
+		}
+	}
+	for i := range want {
+		want[i] = max(want[i], minTableColumnWidth)
+		need[i] = max(need[i], minTableColumnWidth)
+	}
+	// Spanning cells widen the columns they cover when those are too narrow together
+	separator := displayWidth(borders.middle) + 2
+	for _, cell := range spanning {
+		spread(want[cell.col:cell.col+cell.span], displayWidth(cell.content)-(cell.span-1)*separator)
+		spread(need[cell.col:cell.col+cell.span], longestWord(cell.content)-(cell.span-1)*separator)
+	}
+
+	available := r.width() - borders.overhead(numCols)
+	r.tableColumnWidths = want
+
+	// When long words cannot all be kept whole, no column may claim more than a fair
+	// share for them; they are broken instead of squeezing the other columns
+	if sum(need) > available {
+		fair := max(available/numCols, minTableColumnWidth)
+		for i := range need {
+			need[i] = min(need[i], fair)
+		}
+	}
+
+	switch {
+	case sum(want) <= available:
+		// Everything fits on one line per row
+
+	case r.opts.TruncateTableCells:
+		// Short columns keep their content; long ones share the rest and are cut
+		floor := make([]int, numCols)
+		for i := range floor {
+			floor[i] = min(want[i], minReadableColumnWidth)
+		}
+		r.tableColumnWidths = shareWidth(floor, want, available)
+
+	default:
+		r.tableColumnWidths = shareWidth(need, want, available)
+	}
+
+	// Nested content can leave less room than even the narrowest columns need
+	if sum(r.tableColumnWidths) > available {
+		return false
+	}
+	for i, width := range r.tableColumnWidths {
+		if width < min(want[i], minReadableColumnWidth) {
+			return false
+		}
+	}
+	return true
+}
+
+// shareWidth grows each column from its floor towards its goal, handing out the width
+// left over after the floors in proportion to how far each column is from its goal.
+// No column ends up narrower than minTableColumnWidth, taking the excess from the widest.
+func shareWidth(floor, goal []int, available int) []int {
+	widths := make([]int, len(goal))
+	extra := available - sum(floor)
+	gap := sum(goal) - sum(floor)
+	for i := range widths {
+		widths[i] = floor[i]
+		if gap > 0 && extra > 0 {
+			widths[i] += (goal[i] - floor[i]) * extra / gap
+		}
+		widths[i] = max(widths[i], minTableColumnWidth)
+	}
+
+	// Hand out the columns lost to rounding, widest goal first
+	for spare := available - sum(widths); spare > 0; spare-- {
+		best := -1
+		for i := range widths {
+			if widths[i] < goal[i] && (best < 0 || goal[i]-widths[i] > goal[best]-widths[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		widths[best]++
+	}
+
+	// Raising narrow columns to the minimum can overshoot, so take the excess from the widest
+	for excess := sum(widths) - available; excess > 0; excess-- {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minTableColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// sum adds up widths
+func sum(widths []int) int {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	return total
+}
+
+// spread widens columns evenly until together they are at least total wide.
+func spread(widths []int, total int) {
+	for i := 0; sum(widths) < total; i = (i + 1) % len(widths) {
+		widths[i]++
+	}
+}
+
+// longestWord returns the width of the widest word in s.
+func longestWord(s string) int {
+	longest := 0
+	for _, word := range splitWords(s) {
+		longest = max(longest, displayWidth(word))
+	}
+	return longest
+}
+
+// renderTable renders the collected table data
+func (r *ANSIRenderer) renderTable() string {
+	if len(r.tableRows) == 0 {
+		return ""
+	}
+
+	r.tableAlignments = r.columnAlignments(r.tableColumnCount())
+	readable := r.calculateTableColumnWidths()
+	// A header without body rows has nothing to stack, so it is always drawn as a grid
+	if r.tableHasRecords() {
+		switch r.opts.TableLayout {
+		case TableLayoutRecords:
+			return r.renderTableRecords()
+		case TableLayoutAuto:
+			if !readable {
+				return r.renderTableRecords()
+			}
+		}
+	}
+
+	borders := r.tableBorders()
+	var result strings.Builder
+
+	// Render top border
+	result.WriteString("\n")
+	result.WriteString(r.tableBorder(borders.top))
+
+	// Render rows
+	for rowIdx, row := range r.tableRows {
+		if rowIdx > 0 {
+			result.WriteString(r.tableRowSeparator(r.tableRows[rowIdx-1].section, row.section, borders))
+		}
+		result.WriteString(r.renderTableRow(row, borders))
+	}
+
+	// Render bottom border
+	result.WriteString(r.tableBorder(borders.bottom))
+
+	return result.String()
+}
+
+// tableRowSeparator returns the line drawn between a row in section prev and the next row in section next:
+// a header line below the header, a footer line above the footer and, when enabled, row lines in between.
+func (r *ANSIRenderer) tableRowSeparator(prev, next tableSection, borders borderSet) string {
+	switch {
+	case prev == sectionHeader && next != sectionHeader:
+		return r.tableBorder(borders.header)
+	case next == sectionFooter && prev != sectionFooter:
+		return r.tableBorder(borders.footer)
+	case r.opts.TableRowSeparators && prev == sectionBody && next == sectionBody:
+		return r.tableBorder(borders.row)
+	}
+	return ""
+}
+
+// tableBorder draws a horizontal border line, or nothing when the border style has no such line.
+func (r *ANSIRenderer) tableBorder(border borderLine) string {
+	if border.fill == "" {
+		return ""
+	}
+	var line strings.Builder
+	line.WriteString(border.left)
+	for i, width := range r.tableColumnWidths {
+		if i > 0 {
+			line.WriteString(border.junction)
+		}
+		fill := strings.Repeat(border.fill, width+2)
+		if border.alignMarks && i < len(r.tableAlignments) {
+			// Mark alignment the way markdown does: ":---", "---:" or ":---:"
+			switch r.tableAlignments[i] {
+			case ast.TableAlignmentLeft:
+				fill = ":" + fill[1:]
+			case ast.TableAlignmentRight:
+				fill = fill[:len(fill)-1] + ":"
+			case ast.TableAlignmentCenter:
+				fill = ":" + fill[1:len(fill)-1] + ":"
+			}
+		}
+		line.WriteString(fill)
+	}
+	line.WriteString(border.right)
+	return r.paint(r.opts.Theme.TableBorder, line.String()) + "\n"
+}
+
+// renderTableRow lays out one row of cells, wrapping each cell within its column
+// and padding shorter cells so the row's lines share a common height.
+// Spanning cells take the width of the columns they cover and the borders between them.
+func (r *ANSIRenderer) renderTableRow(row tableRow, borders borderSet) string {
+	numCols := len(r.tableColumnWidths)
+	cells := placeCells(row, numCols)
+	// Ragged rows leave their last columns empty
+	for col := 0; col < numCols; {
+		if len(cells) > 0 {
+			last := cells[len(cells)-1]
+			col = last.col + last.span
+		}
+		if col >= numCols {
+			break
+		}
+		cells = append(cells, placedCell{tableCell: tableCell{span: 1}, col: col})
+	}
+
+	lines := make([][]string, len(cells))
+	height := 1
+	for i, cell := range cells {
+		width := r.spanWidth(cell.col, cell.span, borders)
+		if r.opts.TruncateTableCells {
+			lines[i] = []string{truncateWidth(cell.content, width, "...")}
+		} else {
+			lines[i] = wrapStyled(cell.content, width)
+		}
+		height = max(height, len(lines[i]))
+	}
+
+	var result strings.Builder
+	for lineIdx := 0; lineIdx < height; lineIdx++ {
+		var line strings.Builder
+		line.WriteString(r.paint(r.opts.Theme.TableBorder, borders.left))
+		for i, cell := range cells {
+			cellContent := ""
+			if lineIdx < len(lines[i]) {
+				cellContent = lines[i][lineIdx]
+			}
+			width := r.spanWidth(cell.col, cell.span, borders)
+
+			var paddedCell string
+			switch r.tableAlignments[cell.col] {
+			case ast.TableAlignmentCenter:
+				paddedCell = padCenter(cellContent, width)
+			case ast.TableAlignmentRight:
+				paddedCell = padLeft(cellContent, width)
+			default: // Left alignment
+				paddedCell = padRight(cellContent, width)
+			}
+
+			// Cells arrive already styled, header and footer cells included
+			if i > 0 {
+				line.WriteString(r.paint(r.opts.Theme.TableBorder, borders.middle))
+			}
+			line.WriteString(" ")
+			line.WriteString(paddedCell)
+			line.WriteString(" ")
+		}
+		line.WriteString(r.paint(r.opts.Theme.TableBorder, borders.right))
+		// Borderless tables would otherwise end every line in padding
+		result.WriteString(strings.TrimRight(line.String(), " "))
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// tableHasRecords reports whether the table has body or footer rows to list as records.
+func (r *ANSIRenderer) tableHasRecords() bool {
+	for _, row := range r.tableRows {
+		if row.section != sectionHeader {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTableRecords draws every body and footer row as a block of "header │ value" lines,
+// separated by rules, for tables with more columns than the width can hold.
+// A spanning cell is listed under the header of the first column it covers.
+func (r *ANSIRenderer) renderTableRecords() string {
+	maxWidth := r.width()
+	numCols := len(r.tableColumnWidths)
+
+	keys := make([]string, numCols)
+	var records []tableRow
+	for _, row := range r.tableRows {
+		if row.section != sectionHeader {
+			records = append(records, row)
+			continue
+		}
+		for _, cell := range placeCells(row, numCols) {
+			if keys[cell.col] == "" {
+				keys[cell.col] = cell.content
+			}
+		}
+	}
+
+	// Keys get the width of the longest header, up to a third of the line
+	keyWidth := minTableColumnWidth
+	for _, key := range keys {
+		keyWidth = max(keyWidth, displayWidth(key))
+	}
+	keyWidth = max(min(keyWidth, maxWidth/3), 1)
+	borders := r.tableBorders()
+	separator := " " + borders.middle + " "
+	valueWidth := max(maxWidth-keyWidth-displayWidth(separator), 1)
+	rule := strings.Repeat(borders.ruleFill(), maxWidth)
+
+	var result strings.Builder
+	result.WriteString("\n")
+	for rowIdx, row := range records {
+		if rowIdx > 0 {
+			result.WriteString(r.paint(r.opts.Theme.TableBorder, rule))
+			result.WriteString("\n")
+		}
+		for _, cell := range placeCells(row, numCols) {
+			keyLines := wrapStyled(keys[cell.col], keyWidth)
+			valueLines := wrapStyled(cell.content, valueWidth)
+			for lineIdx := 0; lineIdx < max(len(keyLines), len(valueLines)); lineIdx++ {
+				keyLine, valueLine := "", ""
+				if lineIdx < len(keyLines) {
+					keyLine = keyLines[lineIdx]
+				}
+				if lineIdx < len(valueLines) {
+					valueLine = valueLines[lineIdx]
+				}
+				line := padRight(keyLine, keyWidth) + r.paint(r.opts.Theme.TableBorder, separator) + valueLine
+				result.WriteString(strings.TrimRight(line, " "))
+				result.WriteString("\n")
+			}
+		}
+	}
+	return result.String()
+}
+
+// widenTableHeader is a block parser hook for tables with body rows that have more
+// cells than the header. The parser sizes a table by its header and drops the cells
+// past it, so the header and delimiter rows of such a table are extended with empty
+// columns before p parses it. Other blocks are left to the parser.
+func widenTableHeader(p *parser.Parser, data []byte) (ast.Node, []byte, int) {
+	// The hook runs at every block, so anything but a header and delimiter row is
+	// turned away after looking at two lines
+	header := tableLine(data)
+	if countLeading(header, ' ') > 3 || !strings.Contains(header, "|") || isTableDelimiterRow(header) {
+		return nil, nil, 0
+	}
+	delimiter := tableLine(data[len(header):])
+	if !isTableDelimiterRow(delimiter) || countTableCells(delimiter) != countTableCells(header) {
+		return nil, nil, 0
+	}
+
+	// Like the parser, the table runs until the first line without a pipe
+	size, widest := len(header)+len(delimiter), 0
+	for size < len(data) {
+		line := tableLine(data[size:])
+		if !strings.Contains(line, "|") {
+			break
+		}
+		widest = max(widest, countTableCells(line))
+		size += len(line)
+	}
+	extra := widest - countTableCells(header)
+	if extra <= 0 {
+		return nil, nil, 0
+	}
+
+	table := extendTableRow(header, strings.Repeat("   |", extra)) +
+		extendTableRow(delimiter, strings.Repeat("---|", extra)) +
+		string(data[len(header)+len(delimiter):size])
+	p.Block([]byte(table))
+	return nil, nil, size
+}
+
+// tableLine returns the first line of data, including its line ending.
+func tableLine(data []byte) string {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return string(data[:i+1])
+	}
+	return string(data)
+}
+
+// extendTableRow appends cells to a table row line, keeping its line ending.
+func extendTableRow(line, cells string) string {
+	row := strings.TrimRight(line, " \t\n")
+	if !strings.HasSuffix(row, "|") || strings.HasSuffix(row, `\|`) {
+		row += " |"
+	}
+	return row + cells + line[len(strings.TrimRight(line, " \t\n")):]
+}
+
+// isTableDelimiterRow reports whether line looks like the "|---|:--:|" row under a
+// table header.
+func isTableDelimiterRow(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.Contains(line, "-") && strings.Trim(line, "-:| \t") == ""
+}
+
+// countTableCells returns the number of columns a table row line covers, counting the
+// cells the way the parser splits them: at unescaped pipes, with code spans at the
+// start of a cell kept whole and every extra pipe after a cell spanning a column.
+func countTableCells(line string) int {
+	line = strings.TrimRight(line, " \t\n")
+	i := countLeading(line, ' ')
+	if i < len(line) && line[i] == '|' {
+		i++
+	}
+
+	cols := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if ticks := countLeading(line[i:], '`'); ticks > 0 {
+			fence := strings.Repeat("`", ticks)
+			if closing := strings.Index(line[i+ticks:], fence); closing >= 0 {
+				i += 2*ticks + closing
+			}
+		}
+		for i < len(line) && (line[i] != '|' || isEscaped(line, i)) {
+			i++
+		}
+		pipes := 0
+		for i < len(line) && line[i] == '|' {
+			i++
+			pipes++
+		}
+		cols += max(pipes, 1)
+	}
+	return cols
+}
+
+// isEscaped reports whether the byte at i is preceded by an odd number of backslashes.
+func isEscaped(s string, i int) bool {
+	n := 0
+	for i-n-1 >= 0 && s[i-n-1] == '\\' {
+		n++
+	}
+	return n%2 == 1
+}
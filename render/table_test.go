@@ -1,6 +1,7 @@
 package render
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -39,3 +40,264 @@ func TestRender_Tables(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_TableInlineFormatting(t *testing.T) {
+	theme := MonochromeTheme()
+	opts := Options{Width: 60, Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+	markdown := "| Name | Value |\n|------|-------|\n| **bold** and *italic* | `code` |\n| [docs](https://example.com) | plain |"
+
+	result := RenderToStringWithOptions(markdown, opts)
+	visible := stripANSI(result)
+
+	tests := []struct {
+		name  string
+		found string
+		in    string
+	}{
+		{name: "Bold text", found: "\x1b[1mbold", in: result},
+		{name: "Italic text", found: "\x1b[3mitalic", in: result},
+		{name: "Link text", found: "\x1b[4mdocs", in: result},
+		{name: "Link URL is kept", found: "docs (https://example.com)", in: visible},
+		{name: "Inline code", found: "code", in: visible},
+		{name: "Plain text between styles", found: "bold and italic", in: visible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !contains(tt.in, tt.found) {
+				t.Errorf("Expected table to contain %q, got:\n%q", tt.found, result)
+			}
+		})
+	}
+
+	// Escape sequences must not count towards the column widths
+	widths := make(map[int]bool)
+	for _, line := range strings.Split(visible, "\n") {
+		if line != "" {
+			widths[displayWidth(line)] = true
+		}
+	}
+	if len(widths) != 1 {
+		t.Errorf("Expected all table lines to have the same width, got:\n%s", visible)
+	}
+}
+
+func TestRender_TableWrapping(t *testing.T) {
+	long := "Cells that are too long for their column are wrapped onto multiple lines instead of being cut"
+	markdown := "| Feature | Description | Status |\n|---|---|:-:|\n| Wrapping | " + long + " | **done** |\n| Supercalifragilisticexpialidocious | x | y |"
+
+	tests := []struct {
+		name     string
+		opts     Options
+		want     []string
+		excludes []string
+	}{
+		{
+			name:     "Wrap by default",
+			opts:     Options{Width: 50},
+			want:     append(strings.Fields(long), "Feature", "Status", "done"),
+			excludes: []string{"..."},
+		},
+		{
+			name: "Short columns stay whole",
+			opts: Options{Width: 50},
+			want: []string{"│ Feature ", " Description ", " Status │"},
+		},
+		{
+			name: "Words longer than a column are broken",
+			opts: Options{Width: 40},
+			want: []string{"│ Supercalif │", "│ ragilistic │"},
+		},
+		{
+			name: "Truncate on request",
+			opts: Options{Width: 50, TruncateTableCells: true},
+			want: []string{"Cells that are", "..."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ColorMode = ColorNever
+			result := RenderToStringWithOptions(markdown, tt.opts)
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > tt.opts.Width {
+				t.Errorf("Expected lines at most %d columns wide, got %d:\n%s", tt.opts.Width, got, result)
+			}
+		})
+	}
+}
+
+func TestParseTableLayout(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    TableLayout
+		wantErr bool
+	}{
+		{input: "auto", want: TableLayoutAuto},
+		{input: "", want: TableLayoutAuto},
+		{input: "grid", want: TableLayoutGrid},
+		{input: "Records", want: TableLayoutRecords},
+		{input: "cards", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTableLayout(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTableLayout(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseTableLayout(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_TableLayout(t *testing.T) {
+	wide := "| A | B | C | D | E | F |\n|---|---|---|---|---|---|\n" +
+		"| alpha beta gamma | delta epsilon | zeta eta | theta iota | kappa lambda | rho sigma tau |\n" +
+		"| 1 | 2 | 3 | 4 | 5 | 6 |"
+	narrow := "| Name | Value |\n|------|-------|\n| width | 40 |"
+
+	tests := []struct {
+		name     string
+		markdown string
+		layout   TableLayout
+		want     []string
+		excludes []string
+	}{
+		{
+			name:     "Auto falls back to records for cramped tables",
+			markdown: wide,
+			want:     []string{"A   │ alpha beta gamma", "F   │ rho sigma tau", "A   │ 1", "────"},
+			excludes: []string{"┌", "┼"},
+		},
+		{
+			name:     "Auto keeps a grid when columns are readable",
+			markdown: narrow,
+			want:     []string{"┌", "│ width │ 40    │"},
+		},
+		{
+			name:     "Grid is kept on request",
+			markdown: wide,
+			layout:   TableLayoutGrid,
+			want:     []string{"┌", "┼"},
+		},
+		{
+			name:     "Records on request",
+			markdown: narrow,
+			layout:   TableLayoutRecords,
+			want:     []string{"Name  │ width", "Value │ 40"},
+			excludes: []string{"┌"},
+		},
+		{
+			name:     "Records on request keep a header-only table as a grid",
+			markdown: "| Name | Value |\n|------|-------|",
+			layout:   TableLayoutRecords,
+			want:     []string{"┌", "│ Name │ Value │"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, ColorMode: ColorNever, TableLayout: tt.layout})
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > 40 {
+				t.Errorf("Expected lines at most 40 columns wide, got %d:\n%s", got, result)
+			}
+		})
+	}
+}
+
+func TestRender_TableSectionsAndSpans(t *testing.T) {
+	markdown := "| Item | Qty | Price |\n|:---|:-:|---:|\n| Apple | 3 | 1.20 |\n| Pear |\n| Merged across two ||  9.99 |\n|===|===|===|\n| Total || 11.19 |"
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Short rows are padded", want: "│ Pear     │        │       │"},
+		{name: "Spanning cells merge columns", want: "│ Merged across two │  9.99 │"},
+		{name: "Footer has its own separator", want: "╞══════════╪════════╪═══════╡\n│ Total             │ 11.19 │\n└"},
+		{name: "Header separator", want: "│ Item     │  Qty   │ Price │\n├──────────┼────────┼───────┤"},
+	}
+
+	result := RenderToStringWithOptions(markdown, Options{Width: 60, ColorMode: ColorNever})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !contains(result, tt.want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.want, result)
+			}
+		})
+	}
+
+	t.Run("Footer style", func(t *testing.T) {
+		styled := RenderToStringWithOptions(markdown, Options{Width: 60, Theme: MonochromeTheme(), ColorMode: ColorAlways})
+		if !contains(styled, "\x1b[3mTotal") {
+			t.Errorf("Expected footer cells in the footer style, got:\n%q", styled)
+		}
+	})
+}
+
+func TestRender_TableRaggedRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{
+			name:     "Short and long rows",
+			markdown: "| A | B |\n|---|---|\n| 1 |\n| 1 | 2 | 3 | 4 |",
+			want:     []string{"│ A   │ B   │     │     │", "│ 1   │     │     │     │", "│ 1   │ 2   │ 3   │ 4   │"},
+		},
+		{
+			name:     "Spans count towards the row length",
+			markdown: "| A |\n|:-:|\n| x || y |",
+			want:     []string{"│  A  │     │     │", "│     x     │ y   │"},
+		},
+		{
+			name:     "Header without outer pipes",
+			markdown: "A | B\n--|--\n1 | 2 | 3",
+			want:     []string{"│ A   │ B   │     │", "│ 1   │ 2   │ 3   │"},
+		},
+		{
+			name:     "Inside a blockquote",
+			markdown: "> | A |\n> |---|\n> | 1 | 2 |",
+			want:     []string{"│ │ A   │     │", "│ │ 1   │ 2   │"},
+		},
+		{
+			name:     "Pipes in code and escaped pipes do not add cells",
+			markdown: "| A | B |\n|---|---|\n| `a|b` | c \\| d |",
+			want:     []string{"│ A   │ B     │", "│ a|b │ c | d │"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: 40, ColorMode: ColorNever})
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"bytes"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// taskMarkers are the GitHub task list markers that may start a list item, and whether each means done.
+var taskMarkers = []struct {
+	marker string
+	done   bool
+}{
+	{"[ ]", false},
+	{"[x]", true},
+	{"[X]", true},
+}
+
+// taskText returns the text node holding the task marker of item, if it has one.
+func taskText(item *ast.ListItem) (*ast.Text, bool) {
+	para, ok := ast.GetFirstChild(item).(*ast.Paragraph)
+	if !ok {
+		return nil, false
+	}
+	text, ok := ast.GetFirstChild(para).(*ast.Text)
+	return text, ok
+}
+
+// taskState reports whether item is a task list item ("- [ ] todo" or "- [x] done")
+// and whether it is done.
+func taskState(item *ast.ListItem) (isTask, done bool) {
+	text, ok := taskText(item)
+	if !ok {
+		return false, false
+	}
+	for _, m := range taskMarkers {
+		rest, found := bytes.CutPrefix(text.Literal, []byte(m.marker))
+		// The marker must be followed by a space, or be the whole item
+		if found && (len(rest) == 0 || rest[0] == ' ') {
+			return true, m.done
+		}
+	}
+	return false, false
+}
+
+// stripTaskMarker removes the task marker and the space after it from item's text.
+func stripTaskMarker(item *ast.ListItem) {
+	if text, ok := taskText(item); ok && len(text.Literal) >= 3 {
+		text.Literal = bytes.TrimPrefix(text.Literal[3:], []byte(" "))
+	}
+}
+
+// countTasks returns how many items of list are tasks and how many of those are done.
+func countTasks(list *ast.List) (done, total int) {
+	for _, child := range list.GetChildren() {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		if isTask, isDone := taskState(item); isTask {
+			total++
+			if isDone {
+				done++
+			}
+		}
+	}
+	return done, total
+}
@@ -0,0 +1,123 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender_TaskLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Open and done tasks",
+			markdown: "- [ ] todo\n- [x] done\n- [X] also done",
+			contains: []string{"☐ todo", "☑ done", "☑ also done"},
+			excludes: []string{"•", "[ ]", "[x]", "[X]"},
+		},
+		{
+			name:     "Mixed list keeps bullets for plain items",
+			markdown: "- [ ] todo\n- plain [x] item",
+			contains: []string{"☐ todo", "• plain [x] item"},
+		},
+		{
+			name:     "Ordered tasks keep their numbers",
+			markdown: "1. [x] first\n2. [ ] second",
+			contains: []string{"1. ☑ first", "2. ☐ second"},
+		},
+		{
+			name:     "Nested tasks",
+			markdown: "- [ ] parent\n  - [x] child",
+			contains: []string{"☐ parent", "  ☑ child"},
+		},
+		{
+			name:     "Brackets without a space are not a task",
+			markdown: "- [x]done",
+			contains: []string{"• [x]done"},
+		},
+		{
+			name:     "No summary by default",
+			markdown: "- [ ] todo\n- [x] done",
+			excludes: []string{"1/2 done"},
+		},
+		{
+			name:     "Summary per list",
+			markdown: "- [ ] a\n- [x] b\n- [x] c\n  - [ ] d\n- plain",
+			opts:     Options{TaskSummary: true},
+			contains: []string{"2/3 done", "  0/1 done"},
+		},
+		{
+			name:     "No summary for lists without tasks",
+			markdown: "- plain\n- list",
+			opts:     Options{TaskSummary: true},
+			excludes: []string{"done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Width = 40
+			tt.opts.ColorMode = ColorNever
+			result := RenderToStringWithOptions(tt.markdown, tt.opts)
+			for _, want := range tt.contains {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if contains(result, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_TaskDoneStyle(t *testing.T) {
+	theme := DarkTheme()
+	theme.TaskDone = Style{Strikethrough: true}
+	opts := Options{Width: 40, Theme: theme, ColorMode: ColorAlways, ColorProfile: ProfileANSI}
+
+	result := RenderToStringWithOptions("- [x] shipped\n- [ ] pending", opts)
+	var done, open string
+	for _, line := range strings.Split(result, "\n") {
+		switch {
+		case contains(stripANSI(line), "shipped"):
+			done = line
+		case contains(stripANSI(line), "pending"):
+			open = line
+		}
+	}
+
+	// 9 is the SGR parameter for crossed-out text
+	if !contains(done, "\x1b[9m") && !contains(done, ";9m") {
+		t.Errorf("Expected done task to be struck through, got %q", done)
+	}
+	if contains(open, "\x1b[9m") || contains(open, ";9m") {
+		t.Errorf("Expected open task not to be struck through, got %q", open)
+	}
+}
+
+func TestParseStyle_TaskGlyphs(t *testing.T) {
+	theme, err := ParseStyle([]byte(`{"decorations": {"task_glyphs": ["[ ]", "[x]"]}, "elements": {"task_done": {"strikethrough": true}}}`))
+	if err != nil {
+		t.Fatalf("ParseStyle() error = %v", err)
+	}
+	if got := theme.taskGlyph(false); got != "[ ]" {
+		t.Errorf("taskGlyph(false) = %q, want %q", got, "[ ]")
+	}
+	if got := theme.taskGlyph(true); got != "[x]" {
+		t.Errorf("taskGlyph(true) = %q, want %q", got, "[x]")
+	}
+	if !theme.TaskDone.Strikethrough {
+		t.Errorf("TaskDone = %+v, want strikethrough", theme.TaskDone)
+	}
+
+	if _, err := ParseStyle([]byte(`{"decorations": {"task_glyphs": ["o"]}}`)); err == nil || !contains(err.Error(), "task_glyphs: has 1 entries") {
+		t.Errorf("ParseStyle() error = %v, want a task_glyphs count error", err)
+	}
+}
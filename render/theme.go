@@ -0,0 +1,529 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ErrUnknownTheme is returned by ThemeByName when no built-in theme has the requested name.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Color is a terminal color: either a basic ANSI name such as "blue" or
+// "hiblack", which follows the terminal's own palette, or a hex value such
+// as "#5f87ff", which is down-sampled to the active ColorProfile.
+// The empty Color leaves the terminal's default color in place.
+type Color string
+
+// namedColors maps color names to their foreground SGR attribute.
+// Background attributes are offset by 10, as defined by ECMA-48.
+var namedColors = map[Color]color.Attribute{
+	"black":     color.FgBlack,
+	"red":       color.FgRed,
+	"green":     color.FgGreen,
+	"yellow":    color.FgYellow,
+	"blue":      color.FgBlue,
+	"magenta":   color.FgMagenta,
+	"cyan":      color.FgCyan,
+	"white":     color.FgWhite,
+	"hiblack":   color.FgHiBlack,
+	"hired":     color.FgHiRed,
+	"higreen":   color.FgHiGreen,
+	"hiyellow":  color.FgHiYellow,
+	"hiblue":    color.FgHiBlue,
+	"himagenta": color.FgHiMagenta,
+	"hicyan":    color.FgHiCyan,
+	"hiwhite":   color.FgHiWhite,
+}
+
+// Style describes how a markdown element is painted.
+// The zero Style prints text unchanged.
+type Style struct {
+	Foreground Color
+	Background Color
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Faint      bool
+	// Strikethrough draws a line through the text.
+	Strikethrough bool
+}
+
+// isZero reports whether s would emit no escape sequences.
+func (s Style) isZero() bool {
+	return s == Style{}
+}
+
+// merge layers o on top of s: o's colors win when set, attributes accumulate.
+func (s Style) merge(o Style) Style {
+	if o.Foreground != "" {
+		s.Foreground = o.Foreground
+	}
+	if o.Background != "" {
+		s.Background = o.Background
+	}
+	s.Bold = s.Bold || o.Bold
+	s.Italic = s.Italic || o.Italic
+	s.Underline = s.Underline || o.Underline
+	s.Faint = s.Faint || o.Faint
+	s.Strikethrough = s.Strikethrough || o.Strikethrough
+	return s
+}
+
+// attributes converts s into the SGR attributes understood by fatih/color.
+func (s Style) attributes(profile ColorProfile) []color.Attribute {
+	var attrs []color.Attribute
+	if s.Bold {
+		attrs = append(attrs, color.Bold)
+	}
+	if s.Faint {
+		attrs = append(attrs, color.Faint)
+	}
+	if s.Italic {
+		attrs = append(attrs, color.Italic)
+	}
+	if s.Underline {
+		attrs = append(attrs, color.Underline)
+	}
+	if s.Strikethrough {
+		attrs = append(attrs, color.CrossedOut)
+	}
+	attrs = append(attrs, s.Foreground.attributes(profile, false)...)
+	attrs = append(attrs, s.Background.attributes(profile, true)...)
+	return attrs
+}
+
+// Theme maps each markdown element to the Style it is painted with.
+type Theme struct {
+	Name string
+
+	// Headings holds the text style for heading levels 1 through 6.
+	Headings      [6]Style
+	HeadingPrefix Style
+
+	Strong        Style
+	Emph          Style
+	Strikethrough Style
+	Highlight     Style
+
+	Code              Style
+	CodeBlock         Style
+	CodeBlockBorder   Style
+	CodeBlockLabel    Style
+	CodeLineNumber    Style
+	CodeHighlightLine Style
+
+	Link     Style
+	LinkURL  Style
+	Image    Style
+	Footnote Style
+	Callout  Style
+
+	Bullet         Style
+	ListGuide      Style
+	DefinitionTerm Style
+	Task           Style
+	TaskDone       Style
+	BlockQuote     Style
+	TableBorder    Style
+	TableHeader    Style
+	TableFooter    Style
+	HorizontalRule Style
+
+	// Syntax holds the token styles used to highlight fenced code blocks.
+	Syntax SyntaxStyles
+	// Alerts holds the box styles of GitHub alerts ("> [!NOTE]") and asides.
+	Alerts AlertStyles
+
+	// Bullets holds the unordered list markers, indexed by nesting depth.
+	// The last glyph is reused for deeper levels.
+	Bullets []string
+	// Numbering holds the ordered list numbering styles, indexed by nesting depth.
+	// The last style is reused for deeper levels, and an empty slice numbers every
+	// level with decimals.
+	Numbering []NumberStyle
+	// HeadingPrefixes holds the marker printed before heading levels 1 through 6.
+	HeadingPrefixes [6]string
+	// TaskGlyphs holds the checkboxes of open and completed task list items.
+	TaskGlyphs [2]string
+}
+
+// SyntaxStyles holds the styles of highlighted source tokens.
+// A zero style falls back to Theme.CodeBlock, which is also used for
+// code in languages the highlighter does not know.
+type SyntaxStyles struct {
+	Text     Style
+	Keyword  Style
+	Type     Style
+	Literal  Style
+	String   Style
+	Number   Style
+	Comment  Style
+	Operator Style
+	Function Style
+	Variable Style
+	Key      Style
+	Inserted Style
+	Deleted  Style
+	Meta     Style
+}
+
+// AlertStyles holds the border and title styles of each kind of alert box.
+type AlertStyles struct {
+	Note      Style
+	Tip       Style
+	Important Style
+	Warning   Style
+	Caution   Style
+	Aside     Style
+}
+
+// defaultBullets and defaultHeadingPrefixes are the decorations shared by the built-in themes.
+var (
+	defaultBullets         = []string{"•", "◦", "▪", "‣"}
+	defaultHeadingPrefixes = [6]string{"# ", "## ", "### ", "#### ", "##### ", "###### "}
+	defaultTaskGlyphs      = [2]string{"☐", "☑"}
+)
+
+// bullet returns the unordered list marker for the given 1-based nesting depth.
+func (t *Theme) bullet(depth int) string {
+	bullets := t.Bullets
+	if len(bullets) == 0 {
+		bullets = defaultBullets
+	}
+	if depth > len(bullets) {
+		depth = len(bullets)
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	return bullets[depth-1]
+}
+
+// numbering returns the numbering style of an ordered list at the given nesting depth.
+func (t *Theme) numbering(depth int) NumberStyle {
+	if len(t.Numbering) == 0 {
+		return NumberDecimal
+	}
+	return t.Numbering[min(max(depth, 1), len(t.Numbering))-1]
+}
+
+// taskGlyph returns the checkbox for an open or completed task list item.
+func (t *Theme) taskGlyph(done bool) string {
+	i := 0
+	if done {
+		i = 1
+	}
+	if t.TaskGlyphs[i] == "" {
+		return defaultTaskGlyphs[i]
+	}
+	return t.TaskGlyphs[i]
+}
+
+// alertStyle returns the box style of an alert of the given kind.
+func (t *Theme) alertStyle(kind alertKind) Style {
+	switch kind {
+	case alertTip:
+		return t.Alerts.Tip
+	case alertImportant:
+		return t.Alerts.Important
+	case alertWarning:
+		return t.Alerts.Warning
+	case alertCaution:
+		return t.Alerts.Caution
+	case alertAside:
+		return t.Alerts.Aside
+	default:
+		return t.Alerts.Note
+	}
+}
+
+// headingPrefix returns the marker printed before a heading of the given level.
+func (t *Theme) headingPrefix(level int) string {
+	if level < 1 || level > len(t.HeadingPrefixes) {
+		return ""
+	}
+	if prefix := t.HeadingPrefixes[level-1]; prefix != "" {
+		return prefix
+	}
+	return defaultHeadingPrefixes[level-1]
+}
+
+// headingStyle returns the text style for a heading of the given level.
+func (t *Theme) headingStyle(level int) Style {
+	if level < 1 || level > len(t.Headings) {
+		return Style{}
+	}
+	return t.Headings[level-1]
+}
+
+// themeElements names every styleable element, as used by style files.
+var themeElements = []struct {
+	name  string
+	style func(t *Theme) *Style
+}{
+	{"heading1", func(t *Theme) *Style { return &t.Headings[0] }},
+	{"heading2", func(t *Theme) *Style { return &t.Headings[1] }},
+	{"heading3", func(t *Theme) *Style { return &t.Headings[2] }},
+	{"heading4", func(t *Theme) *Style { return &t.Headings[3] }},
+	{"heading5", func(t *Theme) *Style { return &t.Headings[4] }},
+	{"heading6", func(t *Theme) *Style { return &t.Headings[5] }},
+	{"heading_prefix", func(t *Theme) *Style { return &t.HeadingPrefix }},
+	{"strong", func(t *Theme) *Style { return &t.Strong }},
+	{"emph", func(t *Theme) *Style { return &t.Emph }},
+	{"strikethrough", func(t *Theme) *Style { return &t.Strikethrough }},
+	{"highlight", func(t *Theme) *Style { return &t.Highlight }},
+	{"code", func(t *Theme) *Style { return &t.Code }},
+	{"code_block", func(t *Theme) *Style { return &t.CodeBlock }},
+	{"code_block_border", func(t *Theme) *Style { return &t.CodeBlockBorder }},
+	{"code_block_label", func(t *Theme) *Style { return &t.CodeBlockLabel }},
+	{"code_line_number", func(t *Theme) *Style { return &t.CodeLineNumber }},
+	{"code_highlight_line", func(t *Theme) *Style { return &t.CodeHighlightLine }},
+	{"link", func(t *Theme) *Style { return &t.Link }},
+	{"link_url", func(t *Theme) *Style { return &t.LinkURL }},
+	{"image", func(t *Theme) *Style { return &t.Image }},
+	{"footnote", func(t *Theme) *Style { return &t.Footnote }},
+	{"callout", func(t *Theme) *Style { return &t.Callout }},
+	{"bullet", func(t *Theme) *Style { return &t.Bullet }},
+	{"list_guide", func(t *Theme) *Style { return &t.ListGuide }},
+	{"definition_term", func(t *Theme) *Style { return &t.DefinitionTerm }},
+	{"task", func(t *Theme) *Style { return &t.Task }},
+	{"task_done", func(t *Theme) *Style { return &t.TaskDone }},
+	{"blockquote", func(t *Theme) *Style { return &t.BlockQuote }},
+	{"table_border", func(t *Theme) *Style { return &t.TableBorder }},
+	{"table_header", func(t *Theme) *Style { return &t.TableHeader }},
+	{"table_footer", func(t *Theme) *Style { return &t.TableFooter }},
+	{"horizontal_rule", func(t *Theme) *Style { return &t.HorizontalRule }},
+	{"syntax_text", func(t *Theme) *Style { return &t.Syntax.Text }},
+	{"syntax_keyword", func(t *Theme) *Style { return &t.Syntax.Keyword }},
+	{"syntax_type", func(t *Theme) *Style { return &t.Syntax.Type }},
+	{"syntax_literal", func(t *Theme) *Style { return &t.Syntax.Literal }},
+	{"syntax_string", func(t *Theme) *Style { return &t.Syntax.String }},
+	{"syntax_number", func(t *Theme) *Style { return &t.Syntax.Number }},
+	{"syntax_comment", func(t *Theme) *Style { return &t.Syntax.Comment }},
+	{"syntax_operator", func(t *Theme) *Style { return &t.Syntax.Operator }},
+	{"syntax_function", func(t *Theme) *Style { return &t.Syntax.Function }},
+	{"syntax_variable", func(t *Theme) *Style { return &t.Syntax.Variable }},
+	{"syntax_key", func(t *Theme) *Style { return &t.Syntax.Key }},
+	{"syntax_inserted", func(t *Theme) *Style { return &t.Syntax.Inserted }},
+	{"syntax_deleted", func(t *Theme) *Style { return &t.Syntax.Deleted }},
+	{"syntax_meta", func(t *Theme) *Style { return &t.Syntax.Meta }},
+	{"alert_note", func(t *Theme) *Style { return &t.Alerts.Note }},
+	{"alert_tip", func(t *Theme) *Style { return &t.Alerts.Tip }},
+	{"alert_important", func(t *Theme) *Style { return &t.Alerts.Important }},
+	{"alert_warning", func(t *Theme) *Style { return &t.Alerts.Warning }},
+	{"alert_caution", func(t *Theme) *Style { return &t.Alerts.Caution }},
+	{"alert_aside", func(t *Theme) *Style { return &t.Alerts.Aside }},
+}
+
+// DarkTheme returns the default palette, tuned for dark terminal backgrounds.
+func DarkTheme() *Theme {
+	heading := Style{Foreground: "#f0f0f0", Bold: true}
+	return &Theme{
+		Name:              "dark",
+		Headings:          [6]Style{heading, heading, heading, heading, heading, heading},
+		HeadingPrefix:     Style{Foreground: "#5f87ff"},
+		Strong:            Style{Foreground: "#87afff", Bold: true},
+		Emph:              Style{Foreground: "#87afff", Italic: true},
+		Strikethrough:     Style{Foreground: "#808080", Strikethrough: true},
+		Highlight:         Style{Foreground: "#1c1c1c", Background: "#ffd75f"},
+		Code:              Style{Foreground: "#ff5f5f"},
+		CodeBlock:         Style{Foreground: "#ff87ff"},
+		CodeBlockBorder:   Style{Foreground: "#6c6c6c"},
+		CodeBlockLabel:    Style{Foreground: "#87afff", Bold: true},
+		CodeLineNumber:    Style{Foreground: "#6c6c6c"},
+		CodeHighlightLine: Style{Background: "#3a3a3a"},
+		Link:              Style{Foreground: "#5f87ff"},
+		LinkURL:           Style{Faint: true},
+		Image:             Style{Foreground: "#d75fd7"},
+		Footnote:          Style{Foreground: "#5fafaf"},
+		Callout:           Style{Foreground: "#ffaf5f", Bold: true},
+		Bullet:            Style{Foreground: "#ffd75f"},
+		ListGuide:         Style{Foreground: "#6c6c6c"},
+		DefinitionTerm:    Style{Foreground: "#f0f0f0", Bold: true},
+		Task:              Style{Foreground: "#ffd75f"},
+		TaskDone:          Style{Foreground: "#808080"},
+		BlockQuote:        Style{Foreground: "#6c6c6c"},
+		TableBorder:       Style{Foreground: "#6c6c6c"},
+		TableHeader:       Style{Foreground: "#f0f0f0", Bold: true},
+		TableFooter:       Style{Foreground: "#bcbcbc", Italic: true},
+		HorizontalRule:    Style{Foreground: "#6c6c6c"},
+		Syntax: SyntaxStyles{
+			Text:     Style{Foreground: "#e4e4e4"},
+			Keyword:  Style{Foreground: "#d787ff", Bold: true},
+			Type:     Style{Foreground: "#5fd7ff"},
+			Literal:  Style{Foreground: "#ffaf5f"},
+			String:   Style{Foreground: "#afd75f"},
+			Number:   Style{Foreground: "#ffaf5f"},
+			Comment:  Style{Foreground: "#808080", Italic: true},
+			Operator: Style{Foreground: "#ff87af"},
+			Function: Style{Foreground: "#87afff"},
+			Variable: Style{Foreground: "#ffd75f"},
+			Key:      Style{Foreground: "#5fd7ff"},
+			Inserted: Style{Foreground: "#5fd75f"},
+			Deleted:  Style{Foreground: "#ff5f5f"},
+			Meta:     Style{Foreground: "#5fafd7", Bold: true},
+		},
+		Alerts: AlertStyles{
+			Note:      Style{Foreground: "#5f87ff"},
+			Tip:       Style{Foreground: "#5fd75f"},
+			Important: Style{Foreground: "#af87ff"},
+			Warning:   Style{Foreground: "#ffd75f"},
+			Caution:   Style{Foreground: "#ff5f5f"},
+			Aside:     Style{Foreground: "#808080"},
+		},
+		Bullets:         slices.Clone(defaultBullets),
+		HeadingPrefixes: defaultHeadingPrefixes,
+		TaskGlyphs:      defaultTaskGlyphs,
+	}
+}
+
+// LightTheme returns a palette with darker colors that stay readable on light backgrounds.
+func LightTheme() *Theme {
+	heading := Style{Foreground: "#1c1c1c", Bold: true}
+	return &Theme{
+		Name:              "light",
+		Headings:          [6]Style{heading, heading, heading, heading, heading, heading},
+		HeadingPrefix:     Style{Foreground: "#005fd7"},
+		Strong:            Style{Foreground: "#0000af", Bold: true},
+		Emph:              Style{Foreground: "#0000af", Italic: true},
+		Strikethrough:     Style{Foreground: "#808080", Strikethrough: true},
+		Highlight:         Style{Background: "#ffff87"},
+		Code:              Style{Foreground: "#af0000"},
+		CodeBlock:         Style{Foreground: "#870087"},
+		CodeBlockBorder:   Style{Foreground: "#8a8a8a"},
+		CodeBlockLabel:    Style{Foreground: "#005fd7", Bold: true},
+		CodeLineNumber:    Style{Foreground: "#8a8a8a"},
+		CodeHighlightLine: Style{Background: "#ffffd7"},
+		Link:              Style{Foreground: "#005fd7"},
+		LinkURL:           Style{Faint: true},
+		Image:             Style{Foreground: "#af00af"},
+		Footnote:          Style{Foreground: "#008787"},
+		Callout:           Style{Foreground: "#d75f00", Bold: true},
+		Bullet:            Style{Foreground: "#d75f00"},
+		ListGuide:         Style{Foreground: "#9e9e9e"},
+		DefinitionTerm:    Style{Foreground: "#1c1c1c", Bold: true},
+		Task:              Style{Foreground: "#d75f00"},
+		TaskDone:          Style{Foreground: "#9e9e9e"},
+		BlockQuote:        Style{Foreground: "#8a8a8a"},
+		TableBorder:       Style{Foreground: "#8a8a8a"},
+		TableHeader:       Style{Foreground: "#1c1c1c", Bold: true},
+		TableFooter:       Style{Foreground: "#4e4e4e", Italic: true},
+		HorizontalRule:    Style{Foreground: "#8a8a8a"},
+		Syntax: SyntaxStyles{
+			Text:     Style{Foreground: "#262626"},
+			Keyword:  Style{Foreground: "#8700af", Bold: true},
+			Type:     Style{Foreground: "#005f87"},
+			Literal:  Style{Foreground: "#af5f00"},
+			String:   Style{Foreground: "#008700"},
+			Number:   Style{Foreground: "#af5f00"},
+			Comment:  Style{Foreground: "#8a8a8a", Italic: true},
+			Operator: Style{Foreground: "#af005f"},
+			Function: Style{Foreground: "#005fd7"},
+			Variable: Style{Foreground: "#af8700"},
+			Key:      Style{Foreground: "#005f87"},
+			Inserted: Style{Foreground: "#008700"},
+			Deleted:  Style{Foreground: "#d70000"},
+			Meta:     Style{Foreground: "#0087af", Bold: true},
+		},
+		Alerts: AlertStyles{
+			Note:      Style{Foreground: "#005fd7"},
+			Tip:       Style{Foreground: "#008700"},
+			Important: Style{Foreground: "#8700af"},
+			Warning:   Style{Foreground: "#af8700"},
+			Caution:   Style{Foreground: "#d70000"},
+			Aside:     Style{Foreground: "#6c6c6c"},
+		},
+		Bullets:         slices.Clone(defaultBullets),
+		HeadingPrefixes: defaultHeadingPrefixes,
+		TaskGlyphs:      defaultTaskGlyphs,
+	}
+}
+
+// MonochromeTheme returns a palette that uses only text attributes and no colors.
+func MonochromeTheme() *Theme {
+	heading := Style{Bold: true}
+	return &Theme{
+		Name:              "monochrome",
+		Headings:          [6]Style{{Bold: true, Underline: true}, heading, heading, heading, heading, heading},
+		HeadingPrefix:     Style{Bold: true},
+		Strong:            Style{Bold: true},
+		Emph:              Style{Italic: true},
+		Strikethrough:     Style{Strikethrough: true},
+		Highlight:         Style{Bold: true, Underline: true},
+		CodeBlockLabel:    Style{Bold: true},
+		CodeLineNumber:    Style{Faint: true},
+		CodeHighlightLine: Style{Bold: true},
+		Link:              Style{Underline: true},
+		LinkURL:           Style{Faint: true},
+		Callout:           Style{Bold: true},
+		ListGuide:         Style{Faint: true},
+		DefinitionTerm:    Style{Bold: true},
+		TaskDone:          Style{Faint: true},
+		BlockQuote:        Style{Faint: true},
+		TableBorder:       Style{Faint: true},
+		TableHeader:       Style{Bold: true},
+		TableFooter:       Style{Italic: true},
+		HorizontalRule:    Style{Faint: true},
+		Syntax: SyntaxStyles{
+			Keyword:  Style{Bold: true},
+			Comment:  Style{Faint: true, Italic: true},
+			Inserted: Style{Bold: true},
+			Deleted:  Style{Faint: true},
+			Meta:     Style{Underline: true},
+		},
+		Alerts: AlertStyles{
+			Note:      Style{Bold: true},
+			Tip:       Style{Bold: true},
+			Important: Style{Bold: true},
+			Warning:   Style{Bold: true},
+			Caution:   Style{Bold: true},
+			Aside:     Style{Faint: true},
+		},
+		Bullets:         slices.Clone(defaultBullets),
+		HeadingPrefixes: defaultHeadingPrefixes,
+		TaskGlyphs:      defaultTaskGlyphs,
+	}
+}
+
+// builtinThemes lists the themes selectable by name.
+var builtinThemes = map[string]func() *Theme{
+	"dark":       DarkTheme,
+	"light":      LightTheme,
+	"monochrome": MonochromeTheme,
+}
+
+// ThemeNames returns the names of the built-in themes in sorted order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeByName returns a fresh copy of the built-in theme called name.
+func ThemeByName(name string) (*Theme, error) {
+	newTheme, ok := builtinThemes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownTheme, name, strings.Join(ThemeNames(), ", "))
+	}
+	return newTheme(), nil
+}
+
+// paint renders text with style s, leaving it untouched when s is empty or color is off.
+// Color is enabled per call rather than through color.NoColor so that library
+// callers never see fatih/color's global state change.
+func (r *ANSIRenderer) paint(s Style, text string) string {
+	if !r.color || s.isZero() || text == "" {
+		return text
+	}
+	c := color.New(s.attributes(r.profile)...)
+	c.EnableColor()
+	return c.Sprint(text)
+}
@@ -0,0 +1,67 @@
+package render
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestThemeByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		wantErr error
+	}{
+		{name: "Dark", theme: "dark"},
+		{name: "Light", theme: "light"},
+		{name: "Monochrome", theme: "monochrome"},
+		{name: "Case insensitive", theme: "Dark"},
+		{name: "Unknown", theme: "solarized", wantErr: ErrUnknownTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, err := ThemeByName(tt.theme)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ThemeByName(%q) error = %v, want %v", tt.theme, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && theme == nil {
+				t.Fatalf("ThemeByName(%q) returned nil theme", tt.theme)
+			}
+		})
+	}
+}
+
+func TestThemeByName_ReturnsCopy(t *testing.T) {
+	first, _ := ThemeByName("dark")
+	first.Code = Style{Foreground: "green"}
+
+	second, _ := ThemeByName("dark")
+	if second.Code == first.Code {
+		t.Errorf("modifying a theme returned by ThemeByName should not affect later calls")
+	}
+}
+
+func TestRender_Theme_AppliesStyles(t *testing.T) {
+	theme := DarkTheme()
+	theme.Code = Style{Foreground: "green", Underline: true}
+
+	result := RenderToStringWithOptions("Use `go build` here", Options{Theme: theme, ColorMode: ColorAlways})
+
+	// Underline (4) and green foreground (32)
+	if !contains(result, "\x1b[4;32m go build ") {
+		t.Errorf("inline code should use the theme's Code style, got: %q", result)
+	}
+}
+
+func TestRender_MonochromeTheme_NoColors(t *testing.T) {
+	markdown := "# Title\n\n**bold** `code` [link](http://example.com)\n\n- item\n\n> quote\n\n```\ncode\n```\n\n---"
+
+	result := RenderToStringWithOptions(markdown, Options{Theme: MonochromeTheme(), ColorMode: ColorAlways})
+
+	// Foreground and background colors live in the 30-49 and 90-107 SGR ranges
+	colorCode := regexp.MustCompile(`\x1b\[(?:[0-9]+;)*(?:3[0-9]|4[0-9]|9[0-7]|10[0-7])(?:;[0-9]+)*m`)
+	if loc := colorCode.FindStringIndex(result); loc != nil {
+		t.Errorf("monochrome output should not contain color codes, found %q in %q", result[loc[0]:loc[1]], result)
+	}
+}
@@ -0,0 +1,397 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Display width is measured in terminal columns per grapheme cluster, so that
+// CJK text, emoji and accented characters keep borders and padding aligned.
+// Escape sequences (SGR colors and OSC hyperlinks) occupy no columns.
+
+const (
+	// zeroWidthJoiner glues emoji such as 👩‍💻 into a single glyph.
+	zeroWidthJoiner = '\u200d'
+	// emojiPresentation (VS16) asks for a character to be drawn as a two-column emoji.
+	emojiPresentation = '\ufe0f'
+	// sgrReset turns off all colors and attributes.
+	sgrReset = "\x1b[0m"
+)
+
+// wideRanges lists the East Asian Wide and Fullwidth code points and the
+// emoji that terminals draw with emoji presentation by default.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18aff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// runeWidth returns the number of columns r occupies on its own.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x1100:
+		// Fast path for Latin and other narrow scripts, minus their combining marks
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+			return 0
+		}
+		return 1
+	case isZeroWidth(r):
+		return 0
+	}
+
+	// Binary search the sorted wide ranges
+	lo, hi := 0, len(wideRanges)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid].lo:
+			hi = mid - 1
+		case r > wideRanges[mid].hi:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// isZeroWidth reports whether r combines with the preceding character instead of taking a column.
+func isZeroWidth(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) ||
+		(r >= 0x1160 && r <= 0x11ff) || // Hangul medial vowels and final consonants
+		isEmojiModifier(r)
+}
+
+// isEmojiModifier reports whether r is a skin tone modifier.
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+// isRegionalIndicator reports whether r is one half of a flag emoji.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// nextCluster returns the length in bytes and the display width of the grapheme
+// cluster or escape sequence at the start of s.
+func nextCluster(s string) (size, width int) {
+	if s == "" {
+		return 0, 0
+	}
+	if s[0] == '\x1b' {
+		return escapeLength(s), 0
+	}
+
+	base, size := utf8.DecodeRuneInString(s)
+	width = runeWidth(base)
+	regionalPair := false
+
+	for size < len(s) {
+		r, n := utf8.DecodeRuneInString(s[size:])
+		switch {
+		case r == zeroWidthJoiner:
+			// The joiner glues the following character into the same glyph
+			size += n
+			if size < len(s) {
+				_, joined := utf8.DecodeRuneInString(s[size:])
+				size += joined
+			}
+			continue
+		case r == emojiPresentation:
+			width = 2
+		case isRegionalIndicator(base) && isRegionalIndicator(r) && !regionalPair:
+			regionalPair = true
+			width = 2
+		case !isZeroWidth(r):
+			return size, width
+		}
+		size += n
+	}
+	return size, width
+}
+
+// escapeLength returns the length of the escape sequence at the start of s.
+// CSI sequences end at a final byte in 0x40-0x7e; OSC sequences such as
+// hyperlinks end at BEL or ST (ESC \).
+func escapeLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
+
+// displayWidth returns the number of terminal columns s occupies, ignoring escape sequences.
+func displayWidth(s string) int {
+	width := 0
+	for s != "" {
+		size, w := nextCluster(s)
+		width += w
+		s = s[size:]
+	}
+	return width
+}
+
+// splitAtWidth splits s into a head at most width columns wide and the remainder,
+// never cutting through a grapheme cluster. At least one cluster is taken so
+// callers always make progress, even when a wide character exceeds width.
+func splitAtWidth(s string, width int) (head, rest string) {
+	used, i := 0, 0
+	for i < len(s) {
+		size, w := nextCluster(s[i:])
+		if used+w > width && used > 0 {
+			break
+		}
+		used += w
+		i += size
+		if used >= width {
+			// Keep trailing escape sequences such as resets with the head
+			for i < len(s) && s[i] == '\x1b' {
+				i += escapeLength(s[i:])
+			}
+			break
+		}
+	}
+	return s[:i], s[i:]
+}
+
+// truncateWidth shortens s to at most width columns, ending it with tail when anything is cut.
+func truncateWidth(s string, width int, tail string) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	tailWidth := displayWidth(tail)
+	if tailWidth > width {
+		tail, tailWidth = "", 0
+	}
+	head, _ := splitAtWidth(s, width-tailWidth)
+	// A wide character may not fit in the remaining space
+	if displayWidth(head) > width-tailWidth {
+		head = ""
+	}
+	// Close any style the cut left open so it does not bleed into the tail and beyond
+	if strings.Contains(head, "\x1b[") {
+		head += sgrReset
+	}
+	return head + tail
+}
+
+// padRight pads s with spaces to width columns.
+func padRight(s string, width int) string {
+	if pad := width - displayWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// padLeft right-aligns s within width columns.
+func padLeft(s string, width int) string {
+	if pad := width - displayWidth(s); pad > 0 {
+		return strings.Repeat(" ", pad) + s
+	}
+	return s
+}
+
+// padCenter centers s within width columns, putting any odd column on the right.
+func padCenter(s string, width int) string {
+	pad := width - displayWidth(s)
+	if pad <= 0 {
+		return s
+	}
+	left := pad / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", pad-left)
+}
+
+// wrapStyled word-wraps s, which may contain escape sequences, into lines at most
+// width columns wide. Styles and hyperlinks open at a line break are closed at the
+// end of the line and reopened at the start of the next one, so each line can be
+// printed on its own between borders without colors bleeding into them.
+func wrapStyled(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	var line strings.Builder
+	var active []string
+	reopen := ""
+	lineWidth := 0
+
+	breakLine := func() {
+		text := line.String()
+		active = escapeState(active, text[len(reopen):])
+		lines = append(lines, text+closeEscapes(active))
+		reopen = strings.Join(active, "")
+		line.Reset()
+		line.WriteString(reopen)
+		lineWidth = 0
+	}
+
+	for _, word := range splitWords(s) {
+		wordWidth := displayWidth(word)
+		if lineWidth > 0 && lineWidth+1+wordWidth > width {
+			breakLine()
+		}
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+		// Words wider than the line are broken wherever the width runs out
+		for lineWidth+wordWidth > width {
+			head, rest := splitAtWidth(word, width)
+			line.WriteString(head)
+			breakLine()
+			word, wordWidth = rest, displayWidth(rest)
+		}
+		line.WriteString(word)
+		lineWidth += wordWidth
+	}
+
+	if text := line.String(); displayWidth(text) > 0 || len(lines) == 0 {
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// splitWords splits s at runs of spaces. Escape sequences stay attached to the
+// neighbouring word so the order of style changes is preserved.
+func splitWords(s string) []string {
+	var words []string
+	start := -1
+	for i := 0; i < len(s); {
+		size, _ := nextCluster(s[i:])
+		if s[i] == ' ' {
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i += size
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// escapeState returns the escape sequences still in effect after printing s,
+// given those in effect before it. SGR resets clear the styles and an OSC 8
+// sequence with an empty target closes the open hyperlink.
+func escapeState(active []string, s string) []string {
+	for i := 0; i < len(s); {
+		size, _ := nextCluster(s[i:])
+		if s[i] == '\x1b' {
+			seq := s[i : i+size]
+			switch {
+			case isSGR(seq) && isSGRReset(seq):
+				active = keepEscapes(active, isHyperlink)
+			case isSGR(seq):
+				active = append(active, seq)
+			case isHyperlink(seq):
+				active = keepEscapes(active, func(e string) bool { return !isHyperlink(e) })
+				if !isHyperlinkEnd(seq) {
+					active = append(active, seq)
+				}
+			}
+		}
+		i += size
+	}
+	return active
+}
+
+// closeEscapes returns the sequences that end the styles and hyperlinks in active.
+func closeEscapes(active []string) string {
+	closing := ""
+	styled := false
+	for _, seq := range active {
+		if isHyperlink(seq) {
+			closing += hyperlinkEnd
+		} else {
+			styled = true
+		}
+	}
+	if styled {
+		closing = sgrReset + closing
+	}
+	return closing
+}
+
+// hyperlinkEnd closes an OSC 8 hyperlink.
+const hyperlinkEnd = "\x1b]8;;\x1b\\"
+
+func isSGR(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m")
+}
+
+// isSGRReset reports whether an SGR sequence ends by resetting every attribute,
+// as in "\x1b[0m", "\x1b[m" or "\x1b[22;0m".
+func isSGRReset(seq string) bool {
+	params := strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b["), "m")
+	last := params[strings.LastIndexByte(params, ';')+1:]
+	return last == "" || last == "0"
+}
+
+func isHyperlink(seq string) bool {
+	return strings.HasPrefix(seq, "\x1b]8;")
+}
+
+// isHyperlinkEnd reports whether an OSC 8 sequence has an empty target, which closes the link.
+func isHyperlinkEnd(seq string) bool {
+	body := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b]8;"), "\a"), "\x1b\\")
+	_, target, _ := strings.Cut(body, ";")
+	return target == ""
+}
+
+// keepEscapes returns the sequences in active for which keep reports true.
+func keepEscapes(active []string, keep func(string) bool) []string {
+	var kept []string
+	for _, seq := range active {
+		if keep(seq) {
+			kept = append(kept, seq)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,192 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "ASCII", text: "hello", want: 5},
+		{name: "Accented precomposed", text: "café", want: 4},
+		{name: "Combining acute accent", text: "cafe\u0301", want: 4},
+		{name: "CJK", text: "日本語", want: 6},
+		{name: "Hangul", text: "한국어", want: 6},
+		{name: "Fullwidth letters", text: "ＡＢ", want: 4},
+		{name: "Emoji", text: "🚀", want: 2},
+		{name: "Emoji with skin tone", text: "👍🏽", want: 2},
+		{name: "ZWJ sequence", text: "👩\u200d💻", want: 2},
+		{name: "Emoji presentation selector", text: "❤\ufe0f", want: 2},
+		{name: "Text presentation", text: "❤", want: 1},
+		{name: "Flag", text: "🇮🇹", want: 2},
+		{name: "SGR escapes", text: "\x1b[1;31mred\x1b[0m", want: 3},
+		{name: "OSC 8 hyperlink", text: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", want: 4},
+		{name: "Box drawing", text: "┌─┐", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.text); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{name: "Fits", text: "short", width: 5, want: "short"},
+		{name: "ASCII", text: "truncated", width: 6, want: "tru..."},
+		{name: "Wide characters are not split", text: "日本語テキスト", width: 8, want: "日本..."},
+		{name: "Wide character does not fit", text: "日本語テキスト", width: 7, want: "日本..."},
+		{name: "Combining marks stay attached", text: "e\u0301e\u0301e\u0301e\u0301e\u0301", width: 4, want: "e\u0301..."},
+		{name: "Open styles are reset", text: "\x1b[1mbold text\x1b[0m", width: 7, want: "\x1b[1mbold\x1b[0m..."},
+		{name: "ZWJ sequence is kept whole", text: "👩\u200d💻👩\u200d💻👩\u200d💻", width: 5, want: "👩\u200d💻..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateWidth(tt.text, tt.width, "...")
+			if got != tt.want {
+				t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			if w := displayWidth(got); w > tt.width {
+				t.Errorf("truncateWidth(%q, %d) is %d columns wide", tt.text, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestWideCharacterLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		width    int
+		want     []string
+	}{
+		{
+			name:     "CJK table cells",
+			markdown: "| 名前 | 説明 |\n|------|------|\n| 東京 | 日本の首都 |\n| café | ok |",
+			width:    40,
+			want:     []string{"│ 名前 │ 説明       │", "│ 東京 │ 日本の首都 │", "│ café │ ok         │"},
+		},
+		{
+			name:     "Emoji table cells",
+			markdown: "| Status | Note |\n|:------:|------|\n| ✅ | 🚀 launch |\n| ❌ | 👩\u200d💻 dev |",
+			width:    40,
+			want:     []string{"│   ✅   │ 🚀 launch │", "│   ❌   │ 👩\u200d💻 dev    │"},
+		},
+		{
+			name:     "Truncated wide table cell",
+			markdown: "| a | b |\n|---|---|\n| " + strings.Repeat("漢字", 20) + " | x |",
+			width:    30,
+		},
+		{
+			name:     "Code block with wide characters",
+			markdown: "```\nfmt.Println(\"こんにちは世界\")\n```",
+			width:    40,
+			want:     []string{"│ fmt.Println(\"こんにちは世界\")        │"},
+		},
+		{
+			name:     "Wrapped code line with wide characters",
+			markdown: "```\n" + strings.Repeat("語", 30) + "\n```",
+			width:    30,
+		},
+		{
+			name:     "Wrapped paragraph with wide words",
+			markdown: strings.Repeat("日本語の文章 ", 20),
+			width:    30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderToStringWithOptions(tt.markdown, Options{Width: tt.width, ColorMode: ColorNever})
+			for _, want := range tt.want {
+				if !contains(result, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, result)
+				}
+			}
+			if got := maxVisibleLineWidth(result); got > tt.width {
+				t.Errorf("Expected lines at most %d columns wide, got %d:\n%s", tt.width, got, result)
+			}
+			// Every line of a box must end at the same column
+			lineWidth := -1
+			for _, line := range strings.Split(result, "\n") {
+				if !strings.HasPrefix(line, "│") && !strings.HasPrefix(line, "┌") && !strings.HasPrefix(line, "└") {
+					continue
+				}
+				if lineWidth >= 0 && displayWidth(line) != lineWidth {
+					t.Errorf("Expected box lines to align, got:\n%s", result)
+					break
+				}
+				lineWidth = displayWidth(line)
+			}
+		})
+	}
+}
+
+func TestWrapStyled(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{
+			name:  "Plain words",
+			text:  "one two three four",
+			width: 9,
+			want:  []string{"one two", "three", "four"},
+		},
+		{
+			name:  "Long word is broken",
+			text:  "abcdefghij",
+			width: 4,
+			want:  []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:  "Style is closed and reopened across lines",
+			text:  "\x1b[1mbold words here\x1b[0m after",
+			width: 10,
+			want:  []string{"\x1b[1mbold words\x1b[0m", "\x1b[1mhere\x1b[0m after"},
+		},
+		{
+			name:  "Hyperlink is closed and reopened across lines",
+			text:  "\x1b]8;;https://example.com\x1b\\click this link\x1b]8;;\x1b\\",
+			width: 10,
+			want:  []string{"\x1b]8;;https://example.com\x1b\\click this" + hyperlinkEnd, "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"},
+		},
+		{
+			name:  "Wide characters",
+			text:  "日本語 テキスト",
+			width: 8,
+			want:  []string{"日本語", "テキスト"},
+		},
+		{
+			name:  "Empty",
+			text:  "",
+			width: 5,
+			want:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapStyled(tt.text, tt.width)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapStyled(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}